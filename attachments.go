@@ -0,0 +1,133 @@
+package challonge
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"strconv"
+	"time"
+)
+
+type Attachment struct {
+	Id               int        `json:"id"`
+	MatchId          int        `json:"match_id"`
+	UserId           int        `json:"user_id"`
+	Description      string     `json:"description"`
+	Url              string     `json:"url"`
+	OriginalFileName string     `json:"original_file_name"`
+	AssetFileName    string     `json:"asset_file_name"`
+	AssetContentType string     `json:"asset_content_type"`
+	AssetFileSize    int        `json:"asset_file_size"`
+	AssetUrl         string     `json:"asset_url"`
+	CreatedAt        *time.Time `json:"created_at,omitempty"`
+	UpdatedAt        *time.Time `json:"updated_at,omitempty"`
+}
+
+type AttachmentItem struct {
+	Attachment Attachment `json:"match_attachment"`
+}
+
+// AttachmentInput describes an attachment to create or update. At least one
+// of File, Url or Description has to be set when creating.
+type AttachmentInput struct {
+	Url         string
+	Description string
+
+	// File is uploaded as the attachment asset under FileName.
+	File     io.Reader
+	FileName string
+}
+
+/** encodes the input as a multipart form */
+func (a AttachmentInput) body() (*bytes.Buffer, string, error) {
+	body := &bytes.Buffer{}
+	w := multipart.NewWriter(body)
+	if a.Url != "" {
+		w.WriteField("match_attachment[url]", a.Url)
+	}
+	if a.Description != "" {
+		w.WriteField("match_attachment[description]", a.Description)
+	}
+	if a.File != nil {
+		name := a.FileName
+		if name == "" {
+			name = "attachment"
+		}
+		part, err := w.CreateFormFile("match_attachment[asset]", name)
+		if err != nil {
+			return nil, "", err
+		}
+		if _, err := io.Copy(part, a.File); err != nil {
+			return nil, "", err
+		}
+	}
+	if err := w.Close(); err != nil {
+		return nil, "", err
+	}
+	return body, w.FormDataContentType(), nil
+}
+
+func (m *Match) attachmentsRoute() string {
+	return fmt.Sprintf("tournaments/%d/matches/%d/attachments", m.TournamentId, m.Id)
+}
+
+/** lists the attachments of a match */
+func (m *Match) GetAttachments() ([]*Attachment, error) {
+	url := client.buildUrl(m.attachmentsRoute(), nil)
+	response := []AttachmentItem{}
+	if err := doRequest(context.Background(), "GET", url, "", nil, &response); err != nil {
+		return nil, fmt.Errorf("unable to list attachments: %v", err)
+	}
+	attachments := make([]*Attachment, 0, len(response))
+	for i := range response {
+		attachments = append(attachments, &response[i].Attachment)
+	}
+	return attachments, nil
+}
+
+/** returns a single attachment of a match */
+func (m *Match) GetAttachment(id int) (*Attachment, error) {
+	url := client.buildUrl(m.attachmentsRoute()+"/"+strconv.Itoa(id), nil)
+	response := &AttachmentItem{}
+	if err := doRequest(context.Background(), "GET", url, "", nil, response); err != nil {
+		return nil, fmt.Errorf("unable to retrieve attachment: %v", err)
+	}
+	return &response.Attachment, nil
+}
+
+/** adds an attachment to a match, uploading a.File if set */
+func (m *Match) AddAttachment(a AttachmentInput) (*Attachment, error) {
+	if a.File == nil && a.Url == "" && a.Description == "" {
+		return nil, fmt.Errorf("attachment needs a file, url or description")
+	}
+	return m.sendAttachment("POST", m.attachmentsRoute(), a)
+}
+
+/** changes an attachment, only the fields set in a are sent */
+func (m *Match) UpdateAttachment(id int, a AttachmentInput) (*Attachment, error) {
+	return m.sendAttachment("PUT", m.attachmentsRoute()+"/"+strconv.Itoa(id), a)
+}
+
+/** removes an attachment from a match */
+func (m *Match) RemoveAttachment(id int) error {
+	url := client.buildUrl(m.attachmentsRoute()+"/"+strconv.Itoa(id), nil)
+	if err := doRequest(context.Background(), "DELETE", url, "", nil, nil); err != nil {
+		return fmt.Errorf("unable to delete attachment: %v", err)
+	}
+	return nil
+}
+
+func (m *Match) sendAttachment(method string, route string, a AttachmentInput) (*Attachment, error) {
+	body, contentType, err := a.body()
+	if err != nil {
+		return nil, err
+	}
+	url := client.buildUrl(route, nil)
+	response := &AttachmentItem{}
+	if err := doRequest(context.Background(), method, url, contentType, body, response); err != nil {
+		return nil, fmt.Errorf("unable to save attachment: %v", err)
+	}
+	return &response.Attachment, nil
+}
@@ -0,0 +1,166 @@
+package challonge
+
+import "sort"
+
+// Bracket is the graph formed by the prerequisite match ids of a tournament.
+// Every match points to the match its winner advances to and, in double
+// elimination, to the match its loser drops to. Round robin and swiss
+// tournaments produce a bracket without edges.
+type Bracket struct {
+	matches  map[int]*Match
+	winnerTo map[int]*Match
+	loserTo  map[int]*Match
+	ordered  []*Match
+}
+
+/** builds the bracket graph of the matches in t */
+func NewBracket(t *Tournament) *Bracket {
+	b := &Bracket{
+		matches:  make(map[int]*Match, len(t.Matches)),
+		winnerTo: make(map[int]*Match),
+		loserTo:  make(map[int]*Match),
+		ordered:  make([]*Match, 0, len(t.Matches)),
+	}
+	for _, m := range t.Matches {
+		b.matches[m.Id] = m
+		b.ordered = append(b.ordered, m)
+	}
+	sort.SliceStable(b.ordered, func(i, j int) bool {
+		return b.ordered[i].Id < b.ordered[j].Id
+	})
+	for _, m := range b.ordered {
+		b.link(m, m.PlayerOnePrereqMatch, m.PlayerOneIsPrereqMatchLoser)
+		b.link(m, m.PlayerTwoPrereqMatch, m.PlayerTwoIsPrereqMatchLoser)
+	}
+	return b
+}
+
+func (b *Bracket) link(m *Match, prereq *int, loser bool) {
+	if prereq == nil {
+		return
+	}
+	if _, ok := b.matches[*prereq]; !ok {
+		return
+	}
+	if loser {
+		b.loserTo[*prereq] = m
+	} else {
+		b.winnerTo[*prereq] = m
+	}
+}
+
+/** returns the match with the given id, or nil */
+func (b *Bracket) Match(id int) *Match {
+	return b.matches[id]
+}
+
+/** returns the matches of the bracket ordered by id */
+func (b *Bracket) Matches() []*Match {
+	return b.ordered
+}
+
+/** returns the match the winner of match id advances to, or nil */
+func (b *Bracket) WinnerGoesTo(id int) *Match {
+	return b.winnerTo[id]
+}
+
+/** returns the match the loser of match id drops to, or nil when the loser is out */
+func (b *Bracket) LoserDropsTo(id int) *Match {
+	return b.loserTo[id]
+}
+
+/** returns the matches feeding match id, player one's first */
+func (b *Bracket) Prerequisites(id int) []*Match {
+	m := b.matches[id]
+	if m == nil {
+		return nil
+	}
+	prereqs := make([]*Match, 0, 2)
+	for _, p := range []*int{m.PlayerOnePrereqMatch, m.PlayerTwoPrereqMatch} {
+		if p != nil && b.matches[*p] != nil {
+			prereqs = append(prereqs, b.matches[*p])
+		}
+	}
+	return prereqs
+}
+
+/** losers bracket matches have a negative round */
+func (b *Bracket) IsLosersBracket(id int) bool {
+	m := b.matches[id]
+	return m != nil && m.Round < 0
+}
+
+// IsGrandFinalReset reports whether match id is the second grand final,
+// played when the losers bracket finalist wins the first. Its players are
+// the winner and loser of the first grand final, which is itself fed by
+// earlier matches; with two players the first grand final also takes the
+// winner and loser of a single match, but that one is the opening match.
+func (b *Bracket) IsGrandFinalReset(id int) bool {
+	m := b.matches[id]
+	if m == nil || m.PlayerOnePrereqMatch == nil || m.PlayerTwoPrereqMatch == nil ||
+		*m.PlayerOnePrereqMatch != *m.PlayerTwoPrereqMatch ||
+		m.PlayerOneIsPrereqMatchLoser == m.PlayerTwoIsPrereqMatchLoser {
+		return false
+	}
+	return len(b.Prerequisites(*m.PlayerOnePrereqMatch)) > 0
+}
+
+// IsThirdPlaceMatch reports whether match id is the bronze match of a single
+// elimination bracket, played between the two losing semi-finalists.
+func (b *Bracket) IsThirdPlaceMatch(id int) bool {
+	m := b.matches[id]
+	return m != nil && m.Round > 0 && m.PlayerOnePrereqMatch != nil && m.PlayerTwoPrereqMatch != nil &&
+		m.PlayerOneIsPrereqMatchLoser && m.PlayerTwoIsPrereqMatchLoser &&
+		*m.PlayerOnePrereqMatch != *m.PlayerTwoPrereqMatch
+}
+
+// GrandFinal returns the final of the bracket, the first grand final when
+// there is a reset match, or nil when the bracket has no edges.
+func (b *Bracket) GrandFinal() *Match {
+	if len(b.winnerTo) == 0 {
+		return nil
+	}
+	for _, m := range b.ordered {
+		if b.IsGrandFinalReset(m.Id) {
+			return b.matches[*m.PlayerOnePrereqMatch]
+		}
+	}
+	var final *Match
+	for _, m := range b.ordered {
+		if m.Round <= 0 || b.winnerTo[m.Id] != nil || b.IsThirdPlaceMatch(m.Id) {
+			continue
+		}
+		if final == nil || m.Round > final.Round {
+			final = m
+		}
+	}
+	return final
+}
+
+/** returns the grand final reset match, or nil */
+func (b *Bracket) GrandFinalReset() *Match {
+	for _, m := range b.ordered {
+		if b.IsGrandFinalReset(m.Id) {
+			return m
+		}
+	}
+	return nil
+}
+
+// PathToFinal returns the matches a player has to win, starting with match
+// id and ending with the grand final. A losers bracket match leads through
+// the losers final into the grand final.
+func (b *Bracket) PathToFinal(id int) []*Match {
+	final := b.GrandFinal()
+	path := make([]*Match, 0)
+	for m := b.matches[id]; m != nil; m = b.winnerTo[m.Id] {
+		path = append(path, m)
+		if m == final {
+			return path
+		}
+		if len(path) > len(b.matches) {
+			break
+		}
+	}
+	return nil
+}
@@ -1,13 +1,16 @@
 package challonge
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"log"
 	"net/http"
 	"net/url"
 	"strconv"
+	"strings"
 	"time"
 )
 
@@ -22,6 +25,7 @@ var client *Client
 var debug = false
 
 type tournament Tournament
+type match Match
 
 type Client struct {
 	baseUrl string
@@ -53,6 +57,36 @@ type Tournament struct {
 	GameName          string     `json:"game_name"`
 	Progress          int        `json:"progress_meter"`
 
+	/** settings */
+	DescriptionSource                string     `json:"description_source"`
+	OpenSignup                       bool       `json:"open_signup"`
+	Private                          bool       `json:"private"`
+	Teams                            bool       `json:"teams"`
+	HoldThirdPlaceMatch              bool       `json:"hold_third_place_match"`
+	GrandFinalsModifier              *string    `json:"grand_finals_modifier"`
+	PtsForMatchWin                   string     `json:"pts_for_match_win"`
+	PtsForMatchTie                   string     `json:"pts_for_match_tie"`
+	PtsForGameWin                    string     `json:"pts_for_game_win"`
+	PtsForGameTie                    string     `json:"pts_for_game_tie"`
+	PtsForBye                        string     `json:"pts_for_bye"`
+	SwissRounds                      int        `json:"swiss_rounds"`
+	RankedBy                         string     `json:"ranked_by"`
+	RrPtsForMatchWin                 string     `json:"rr_pts_for_match_win"`
+	RrPtsForMatchTie                 string     `json:"rr_pts_for_match_tie"`
+	RrPtsForGameWin                  string     `json:"rr_pts_for_game_win"`
+	RrPtsForGameTie                  string     `json:"rr_pts_for_game_tie"`
+	AcceptAttachments                bool       `json:"accept_attachments"`
+	HideForum                        bool       `json:"hide_forum"`
+	ShowRounds                       bool       `json:"show_rounds"`
+	HideSeeds                        bool       `json:"hide_seeds"`
+	QuickAdvance                     bool       `json:"quick_advance"`
+	SequentialPairings               bool       `json:"sequential_pairings"`
+	NotifyUsersWhenMatchesOpen       bool       `json:"notify_users_when_matches_open"`
+	NotifyUsersWhenTheTournamentEnds bool       `json:"notify_users_when_the_tournament_ends"`
+	SignupCap                        *int       `json:"signup_cap"`
+	StartAt                          *time.Time `json:"start_at"`
+	CheckInDuration                  *int       `json:"check_in_duration"`
+
 	SubUrl string `json:"sub_url"`
 
 	ParticipantItems []*ParticipantItem `json:"participants,omitempty"`
@@ -63,36 +97,86 @@ type Tournament struct {
 }
 
 type Participant struct {
-	Id         int    `json:"id"`
-	Name       string `json:"display_name"`
-	Misc       string `json:"misc"`
-	Seed       int    `json:"seed"`
-	FinalRank  int    `json:"final_rank"`
-	Wins       int
-	Losses     int
-	TotalScore int
+	Id                int        `json:"id"`
+	TournamentId      int        `json:"tournament_id"`
+	Name              string     `json:"display_name"`
+	Username          string     `json:"username"`
+	Misc              string     `json:"misc"`
+	Seed              int        `json:"seed"`
+	FinalRank         int        `json:"final_rank"`
+	Active            bool       `json:"active"`
+	CreatedAt         *time.Time `json:"created_at,omitempty"`
+	UpdatedAt         *time.Time `json:"updated_at,omitempty"`
+	GroupId           *int       `json:"group_id"`
+	GroupPlayerIds    []int      `json:"group_player_ids"`
+	OnWaitingList     bool       `json:"on_waiting_list"`
+	Removable         bool       `json:"removable"`
+	Reactivatable     bool       `json:"reactivatable"`
+	HasIrrelevantSeed bool       `json:"has_irrelevant_seed"`
+	Icon              *string    `json:"icon"`
+	PortraitUrl       *string    `json:"attached_participatable_portrait_url"`
+
+	/** check-in */
+	CheckedIn   bool       `json:"checked_in"`
+	CheckedInAt *time.Time `json:"checked_in_at"`
+	CanCheckIn  bool       `json:"can_check_in"`
+	CheckInOpen bool       `json:"check_in_open"`
+
+	/** linked challonge account and invitation */
+	ChallongeUsername             *string `json:"challonge_username"`
+	ChallongeEmailAddressVerified *bool   `json:"challonge_email_address_verified"`
+	EmailHash                     *string `json:"email_hash"`
+	InviteEmail                   *string `json:"invite_email"`
+	InvitationId                  *int    `json:"invitation_id"`
+	InvitationPending             bool    `json:"invitation_pending"`
+	ParticipatableOrInvitation    bool    `json:"participatable_or_invitation_attached"`
+	RankedMemberId                *int    `json:"ranked_member_id"`
+	CustomFieldResponse           *string `json:"custom_field_response"`
 }
 
 type Match struct {
-	Id                   int    `json:"id"`
-	Identifier           string `json:"identifier"`
-	State                string `json:"state"`
-	Round                int    `json:"round"`
-	PlayerOneId          int    `json:"player1_id"`
-	PlayerOnePrereqMatch *int   `json:"player1_prereq_match_id"`
-	PlayerTwoId          int    `json:"player2_id"`
-	PlayerTwoPrereqMatch *int   `json:"player2_prereq_match_id"`
-	PlayerOneScore       int
-	PlayerTwoScore       int
-	UpdatedAt            *time.Time `json:"updated_at,omitempty"`
+	Id                          int        `json:"id"`
+	TournamentId                int        `json:"tournament_id"`
+	Identifier                  string     `json:"identifier"`
+	State                       string     `json:"state"`
+	Round                       int        `json:"round"`
+	GroupId                     *int       `json:"group_id"`
+	SuggestedPlayOrder          *int       `json:"suggested_play_order"`
+	Optional                    *bool      `json:"optional"`
+	PlayerOneId                 int        `json:"player1_id"`
+	PlayerOnePrereqMatch        *int       `json:"player1_prereq_match_id"`
+	PlayerOneIsPrereqMatchLoser bool       `json:"player1_is_prereq_match_loser"`
+	PlayerTwoId                 int        `json:"player2_id"`
+	PlayerTwoPrereqMatch        *int       `json:"player2_prereq_match_id"`
+	PlayerTwoIsPrereqMatchLoser bool       `json:"player2_is_prereq_match_loser"`
+	PrerequisiteMatchIds        string     `json:"prerequisite_match_ids_csv"`
+	ScheduledTime               *time.Time `json:"scheduled_time"`
+	Location                    *string    `json:"location"`
+	CreatedAt                   *time.Time `json:"created_at,omitempty"`
+	UpdatedAt                   *time.Time `json:"updated_at,omitempty"`
+	StartedAt                   *time.Time `json:"started_at"`
+	UnderwayAt                  *time.Time `json:"underway_at"`
+	CompletedAt                 *time.Time `json:"completed_at"`
+	Forfeited                   *bool      `json:"forfeited"`
+	HasAttachment               bool       `json:"has_attachment"`
+	AttachmentCount             *int       `json:"attachment_count"`
 
 	WinnerId int `json:"winner_id"`
+	LoserId  int `json:"loser_id"`
+
+	PlayerOne *Participant `json:"-"`
+	PlayerTwo *Participant `json:"-"`
+	Winner    *Participant `json:"-"`
+
+	Scores string      `json:"scores_csv"`
+	Games  []GameScore `json:"-"`
 
-	PlayerOne *Participant
-	PlayerTwo *Participant
-	Winner    *Participant
+	/** derived from Games: points for a single game, games won otherwise */
+	PlayerOneScore int `json:"-"`
+	PlayerTwoScore int `json:"-"`
 
-	Scores string `json:"scores_csv"`
+	PlayerOneVotes *int `json:"player1_votes"`
+	PlayerTwoVotes *int `json:"player2_votes"`
 }
 
 /** items to flatten json structure */
@@ -162,22 +246,11 @@ type GetTournamentsResponse struct {
 
 // GetTournaments Get tournaments that belongs to your account.
 func (c *Client) GetTournaments(state string, rtype string, subdomain string) ([]*Tournament, error) {
-	v := *params(map[string]string{})
-	v = *params(map[string]string{
-		"state": state, // all, pending, in_progress, ended
-		"type":  rtype, // single elimination, double elimination, round robin, swiss
+	return c.ListTournaments(context.Background(), TournamentQuery{
+		State:     state, // all, pending, in_progress, ended
+		Type:      rtype, // single elimination, double elimination, round robin, swiss
+		Subdomain: subdomain,
 	})
-	if subdomain != "" {
-		v.Set("subdomain", subdomain)
-	}
-	url := client.buildUrl("tournaments", v)
-	response := []GetTournamentsResponse{}
-	doGet(url, &response)
-	tournaments := make([]*Tournament, 0, len(response))
-	for i := 0; i < len(response); i++ {
-		tournaments = append(tournaments, response[i].Tournament)
-	}
-	return tournaments, nil
 }
 
 func (c *Client) NewTournamentRequest(id string) *TournamentRequest {
@@ -210,6 +283,25 @@ func (r *TournamentRequest) Get() (*Tournament, error) {
 	return tournament, nil
 }
 
+// GetContext is like Get, but is bound to ctx and returns network failures
+// as errors.
+func (r *TournamentRequest) GetContext(ctx context.Context) (*Tournament, error) {
+	url := r.client.buildUrl("tournaments/"+r.Id, *params(r.Params))
+	response := &APIResponse{}
+	if err := doRequest(ctx, "GET", url, "", nil, response); err != nil {
+		return nil, fmt.Errorf("unable to retrieve tournament: %w", err)
+	}
+	if response.hasErrors() {
+		return nil, fmt.Errorf("unable to retrieve tournament: %q", response.Errors[0])
+	}
+	if response.Tournament == nil {
+		return nil, fmt.Errorf("unable to retrieve tournament: no tournament in response")
+	}
+	tournament := response.getTournament()
+	tournament.SubUrl = r.Id
+	return tournament, nil
+}
+
 /** creates a new tournament */
 func (c *Client) CreateTournament(name string, subUrl string, domain string, open bool, tType string, desc string) (*Tournament, error) {
 	v := *params(map[string]string{
@@ -358,17 +450,43 @@ func (t *Tournament) Finalize() error {
 }
 
 func (t *Tournament) SubmitMatch(m *Match) (*Match, error) {
+	games := m.Games
+	if len(games) == 0 {
+		games = []GameScore{{PlayerOne: m.PlayerOneScore, PlayerTwo: m.PlayerTwoScore}}
+	} else if one, two := gameTotals(games); one != m.PlayerOneScore || two != m.PlayerTwoScore {
+		// the scores were edited without the games, sending either would lose the other
+		return nil, fmt.Errorf("scores %d-%d of match %d disagree with its games %s, set both with SetScores", m.PlayerOneScore, m.PlayerTwoScore, m.Id, FormatScores(games))
+	}
+	winner := m.WinnerId
+	if winner == 0 {
+		winner = inferWinner(m.PlayerOneId, m.PlayerTwoId, games)
+	}
+	if winner != 0 && winner != m.PlayerOneId && winner != m.PlayerTwoId {
+		return nil, fmt.Errorf("winner %d is not playing match %d", winner, m.Id)
+	}
+	if winner == 0 && t.Type != "round robin" && t.Type != "swiss" {
+		return nil, fmt.Errorf("match %d is tied, ties are only allowed in round robin and swiss", m.Id)
+	}
+
 	v := *params(map[string]string{
-		"match[scores_csv]": fmt.Sprintf("%d-%d", m.PlayerOneScore, m.PlayerTwoScore),
-		"match[winner_id]":  fmt.Sprintf("%d", m.WinnerId),
+		"match[scores_csv]": FormatScores(games),
+		"match[winner_id]":  "tie",
 	})
+	if winner != 0 {
+		v.Set("match[winner_id]", strconv.Itoa(winner))
+	}
+	if m.PlayerOneVotes != nil {
+		v.Set("match[player1_votes]", strconv.Itoa(*m.PlayerOneVotes))
+	}
+	if m.PlayerTwoVotes != nil {
+		v.Set("match[player2_votes]", strconv.Itoa(*m.PlayerTwoVotes))
+	}
 	url := client.buildUrl(fmt.Sprintf("tournaments/%s/matches/%d", t.GetUrl(), m.Id), v)
 	response := &APIResponse{}
 	doPut(url, response)
 	if len(response.Errors) > 0 {
 		return nil, fmt.Errorf("%q", response.Errors[0])
 	}
-	m = &response.Match
 	return &response.Match, nil
 }
 
@@ -488,31 +606,11 @@ func (t *Tournament) GetOpenMatchForParticipant(p *Participant) *Match {
 	return nil
 }
 
-func (p *Participant) Lose() {
-	p.Losses += 1
-}
-
-func (p *Participant) Win() {
-	p.Wins += 1
-}
-
+/** points the player fields of m at the participants of t, missing players stay nil */
 func (m *Match) ResolveParticipants(t *Tournament) {
-	if m.State != "complete" {
-		return
-	}
 	m.PlayerOne = t.GetParticipant(m.PlayerOneId)
 	m.PlayerTwo = t.GetParticipant(m.PlayerTwoId)
-
-	if m.WinnerId == m.PlayerOneId {
-		m.PlayerOne.Win()
-		m.PlayerTwo.Lose()
-	} else if m.WinnerId == m.PlayerTwoId {
-		m.PlayerTwo.Win()
-		m.PlayerOne.Lose()
-	}
-	m.PlayerOne.TotalScore += m.PlayerOneScore
-	m.PlayerTwo.TotalScore += m.PlayerTwoScore
-
+	m.Winner = t.GetParticipant(m.WinnerId)
 }
 
 func (t *Tournament) resolveRelations() *Tournament {
@@ -535,18 +633,13 @@ func (t *Tournament) resolveRelations() *Tournament {
 	return t
 }
 
+/** returns the matches of matches2 that changed compared to matches1, matched by id */
 func DiffMatches(matches1 []*Match, matches2 []*Match) []*Match {
-	diff := make([]*Match, 0, len(matches1))
-
-	for i, _ := range matches1 {
-		if i >= len(matches2) {
-			break
-		}
-		if matches1[i].State != matches2[i].State {
-			diff = append(diff, matches2[i])
-		}
+	changes := DiffMatchesById(matches1, matches2).Changed
+	diff := make([]*Match, 0, len(changes))
+	for _, c := range changes {
+		diff = append(diff, c.New)
 	}
-
 	return diff
 }
 
@@ -601,6 +694,63 @@ func doDelete(url string, v interface{}) {
 	handleResponse(resp, v)
 }
 
+/** error returned by the API, either as a non-2xx status or an "errors" list */
+type APIError struct {
+	StatusCode int      `json:"-"`
+	Errors     []string `json:"errors"`
+}
+
+func (e *APIError) Error() string {
+	if len(e.Errors) == 0 {
+		return fmt.Sprintf("challonge: unexpected status %d", e.StatusCode)
+	}
+	return fmt.Sprintf("challonge: %s", strings.Join(e.Errors, ", "))
+}
+
+// Temporary reports whether retrying the same request may succeed.
+func (e *APIError) Temporary() bool {
+	return e.StatusCode == http.StatusTooManyRequests || e.StatusCode >= 500
+}
+
+// doRequest performs a request bound to ctx and decodes the body into v.
+// Unlike doGet/doPost it hands every failure back to the caller.
+func doRequest(ctx context.Context, method string, url string, contentType string, body io.Reader, v interface{}) error {
+	if debug {
+		log.Printf("%s resource on url %s", strings.ToLower(method), url)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
+	if err != nil {
+		return err
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	b, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode >= 300 {
+		apiErr := &APIError{StatusCode: resp.StatusCode}
+		json.Unmarshal(b, apiErr)
+		return apiErr
+	}
+	if v == nil || len(b) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(b, v); err != nil {
+		return err
+	}
+	if debug {
+		log.Print("unmarshaled to ", v)
+	}
+	return nil
+}
+
 func handleResponse(r *http.Response, v interface{}) {
 	body, err := ioutil.ReadAll(r.Body)
 	if err != nil {
@@ -617,6 +767,18 @@ func handleResponse(r *http.Response, v interface{}) {
 	}
 }
 
+func (m *Match) UnmarshalJSON(b []byte) (err error) {
+	placeholder := match{}
+	if err = json.Unmarshal(b, &placeholder); err != nil {
+		return
+	}
+	*m = Match(placeholder)
+	// an unparsable scores_csv only leaves Games empty
+	m.Games, _ = ParseScores(m.Scores)
+	m.PlayerOneScore, m.PlayerTwoScore = gameTotals(m.Games)
+	return nil
+}
+
 func (t *Tournament) UnmarshalJSON(b []byte) (err error) {
 	placeholder := tournament{}
 	if err = json.Unmarshal(b, &placeholder); err == nil {
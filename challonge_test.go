@@ -1,8 +1,20 @@
 package challonge_test
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
 	"github.com/FlowingSPDG/go-challonge"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 )
 
 const (
@@ -121,3 +133,1103 @@ func TestGetTournaments(t *testing.T) {
 		t.Logf("Item %d : %v\n", i+1, tournaments[i])
 	}
 }
+
+func TestTournamentsIterator(t *testing.T) {
+	client := challonge.New(User, Key)
+	query := challonge.TournamentQuery{
+		State:        "ended",
+		CreatedAfter: time.Now().AddDate(-1, 0, 0),
+	}
+	count := 0
+	for tournament, err := range client.Tournaments(context.Background(), query) {
+		if err != nil {
+			t.Fatalf("unable to list tournaments.\nERR : %v\n", err)
+		}
+		count++
+		t.Logf("Item %d : %v\n", count, tournament)
+	}
+	t.Logf("Got %d items\n", count)
+}
+
+func TestCloneTournament(t *testing.T) {
+	client := challonge.New(User, Key)
+	report, err := client.CloneTournament("sample_tournament_1", &challonge.CloneOptions{WithParticipants: true})
+	if err != nil {
+		t.Fatalf("unable to clone tournament.\nERR : %v\n", err)
+	}
+	t.Logf("Tournament : %v\n", report.Tournament)
+	t.Logf("Copied %d participants\n", len(report.Participants))
+	for _, failure := range report.Failures {
+		t.Errorf("unable to copy %s.\nERR : %v\n", failure.Item, failure.Err)
+	}
+}
+
+func TestBulkAddParticipants(t *testing.T) {
+	client := challonge.New(User, Key)
+	tournament, err := client.NewTournamentRequest("sample_tournament_1").WithParticipants().Get()
+	if err != nil {
+		t.Fatalf("unable to retrieve tournament.\nERR : %v\n", err)
+	}
+	result := tournament.BulkAddParticipants([]challonge.ParticipantInput{
+		{Name: "player one", Seed: 1, Misc: "p1"},
+		{Name: "player two", Seed: 2, Misc: "p2"},
+		{Name: "player three", Misc: "p3"},
+	})
+	for _, err := range result.Errors {
+		t.Errorf("unable to add participant.\nERR : %v\n", err)
+	}
+	t.Logf("Added %d participants\n", len(result.Participants))
+}
+
+func TestBulkAddInvalidEntries(t *testing.T) {
+	challonge.New(User, Key)
+	tournament := &challonge.Tournament{Url: "sample_tournament_1"}
+	result := tournament.BulkAddParticipants([]challonge.ParticipantInput{
+		{Seed: 1},
+		{Name: "player two", Email: "two@example.com", ChallongeUsername: "two"},
+	})
+	if len(result.Errors) != 2 || result.Errors[0].Index != 0 || result.Errors[1].Index != 1 {
+		t.Errorf("expected both entries to be rejected, got %v\n", result.Errors)
+	}
+}
+
+func TestUpdateParticipant(t *testing.T) {
+	client := challonge.New(User, Key)
+	tournament, err := client.NewTournamentRequest("sample_tournament_1").WithParticipants().Get()
+	if err != nil {
+		t.Fatalf("unable to retrieve tournament.\nERR : %v\n", err)
+	}
+	if len(tournament.Participants) < 2 {
+		t.Skip("tournament needs at least two participants")
+	}
+	last := tournament.Participants[len(tournament.Participants)-1]
+	seed := 1
+	p, err := tournament.UpdateParticipant(last.Id, challonge.ParticipantUpdate{Seed: &seed})
+	if err != nil {
+		t.Fatalf("unable to update participant.\nERR : %v\n", err)
+	}
+	if p.Seed != 1 {
+		t.Errorf("expected seed 1, got %d\n", p.Seed)
+	}
+	for _, participant := range tournament.Participants {
+		t.Logf("%s's Seed : %d\n", participant.Name, participant.Seed)
+	}
+}
+
+func TestListParticipantsAndMatches(t *testing.T) {
+	client := challonge.New(User, Key)
+	participants, err := client.GetParticipants("sample_tournament_1")
+	if err != nil {
+		t.Fatalf("unable to list participants.\nERR : %v\n", err)
+	}
+	t.Logf("Got %d participants\n", len(participants))
+
+	matches, err := client.GetMatches("sample_tournament_1", challonge.MatchQuery{State: "open"})
+	if err != nil {
+		t.Fatalf("unable to list matches.\nERR : %v\n", err)
+	}
+	t.Logf("Got %d open matches\n", len(matches))
+	if len(matches) > 0 {
+		match, err := client.ShowMatch("sample_tournament_1", matches[0].Id)
+		if err != nil {
+			t.Fatalf("unable to retrieve match.\nERR : %v\n", err)
+		}
+		t.Logf("Match : %v\n", match)
+	}
+}
+
+func TestApplySeeding(t *testing.T) {
+	client := challonge.New(User, Key)
+	tournament, err := client.NewTournamentRequest("sample_tournament_1").WithParticipants().Get()
+	if err != nil {
+		t.Fatalf("unable to retrieve tournament.\nERR : %v\n", err)
+	}
+	order := make([]int, 0, len(tournament.Participants))
+	for i := len(tournament.Participants) - 1; i >= 0; i-- {
+		order = append(order, tournament.Participants[i].Id)
+	}
+	err = tournament.ApplySeeding(order)
+	if err != nil {
+		t.Fatalf("unable to apply seeding.\nERR : %v\n", err)
+	}
+}
+
+func TestSeedingPlan(t *testing.T) {
+	seeds := map[int]int{101: 1, 102: 2, 103: 3, 104: 4, 105: 5, 106: 6}
+	seed := func(id int) int { return seeds[id] }
+	reversed := []int{106, 105, 104, 103, 102, 101}
+	if keep := challonge.KeptInOrder(reversed, seed); len(keep) != 1 {
+		t.Errorf("reversing should keep a single participant, kept %v\n", keep)
+	}
+	keep := challonge.KeptInOrder([]int{103, 101, 104, 102, 105, 106}, seed)
+	for _, id := range []int{101, 102, 105, 106} {
+		if !keep[id] {
+			t.Errorf("expected %d to stay in place, kept %v\n", id, keep)
+		}
+	}
+
+	orders := [][]int{
+		reversed,
+		{103, 101, 104, 102, 105, 106},
+		{101, 102, 103, 104, 105, 106},
+		{102, 106, 101, 105, 103, 104},
+		{106, 101, 102, 103, 104, 105},
+	}
+	for _, order := range orders {
+		tournament := &challonge.Tournament{}
+		for id := 101; id <= 106; id++ {
+			tournament.Participants = append(tournament.Participants, &challonge.Participant{Id: id, Seed: seeds[id]})
+		}
+		moves := tournament.ReplaySeeding(order)
+		if expected := len(order) - len(challonge.KeptInOrder(order, seed)); moves != expected {
+			t.Errorf("%v : expected %d moves, made %d\n", order, expected, moves)
+		}
+		for i, id := range order {
+			if p := tournament.GetParticipant(id); p.Seed != i+1 {
+				t.Errorf("%v : expected %d at seed %d, got %d\n", order, id, i+1, p.Seed)
+			}
+		}
+	}
+}
+
+func TestParticipantStats(t *testing.T) {
+	client := challonge.New(User, Key)
+	tournament, err := client.NewTournamentRequest("sample_tournament_1").WithParticipants().WithMatches().Get()
+	if err != nil {
+		t.Fatalf("unable to retrieve tournament.\nERR : %v\n", err)
+	}
+	for _, participant := range tournament.Participants {
+		stats := tournament.GetStats(participant.Id)
+		t.Logf("%s (checked in: %v) : %d wins, %d losses\n", participant.Name, participant.CheckedIn, stats.Wins, stats.Losses)
+	}
+}
+
+func TestMarkAsUnderway(t *testing.T) {
+	client := challonge.New(User, Key)
+	tournament, err := client.NewTournamentRequest("sample_tournament_1").WithParticipants().WithMatches().Get()
+	if err != nil {
+		t.Fatalf("unable to retrieve tournament.\nERR : %v\n", err)
+	}
+	matches := tournament.GetOpenMatches()
+	if len(matches) == 0 {
+		t.Skip("tournament has no open matches")
+	}
+	if err := matches[0].MarkAsUnderway(); err != nil {
+		t.Fatalf("unable to mark match as underway.\nERR : %v\n", err)
+	}
+	t.Logf("Underway : %v\n", tournament.GetUnderwayMatches())
+	if err := matches[0].UnmarkAsUnderway(); err != nil {
+		t.Fatalf("unable to unmark match as underway.\nERR : %v\n", err)
+	}
+}
+
+func TestReopenMatch(t *testing.T) {
+	client := challonge.New(User, Key)
+	tournament, err := client.NewTournamentRequest("sample_tournament_1").WithParticipants().WithMatches().Get()
+	if err != nil {
+		t.Fatalf("unable to retrieve tournament.\nERR : %v\n", err)
+	}
+	for _, match := range tournament.GetMatches() {
+		if match.State != "complete" {
+			continue
+		}
+		result, err := tournament.ReopenMatch(match)
+		if err != nil {
+			t.Fatalf("unable to reopen match.\nERR : %v\n", err)
+		}
+		t.Logf("Reopened : %v, reset : %v\n", result.Match, result.Reset)
+		return
+	}
+	t.Skip("tournament has no completed matches")
+}
+
+func TestParseScores(t *testing.T) {
+	games, err := challonge.ParseScores("3-1,1-3,-1-3,3--2")
+	if err != nil {
+		t.Fatalf("unable to parse scores.\nERR : %v\n", err)
+	}
+	expected := []challonge.GameScore{{3, 1}, {1, 3}, {-1, 3}, {3, -2}}
+	if len(games) != len(expected) {
+		t.Fatalf("expected %d games, got %d\n", len(expected), len(games))
+	}
+	for i := range expected {
+		if games[i] != expected[i] {
+			t.Errorf("game %d : expected %v, got %v\n", i, expected[i], games[i])
+		}
+	}
+	if csv := challonge.FormatScores(games); csv != "3-1,1-3,-1-3,3--2" {
+		t.Errorf("unexpected csv %q\n", csv)
+	}
+	if _, err := challonge.ParseScores("3:1"); err == nil {
+		t.Errorf("expected an error for \"3:1\"\n")
+	}
+
+	match := &challonge.Match{PlayerOneId: 1, PlayerTwoId: 2}
+	match.SetScores("3-1,1-3,3-2")
+	if winner := match.InferWinner(); winner != 1 {
+		t.Errorf("expected player 1 to win, got %d\n", winner)
+	}
+	match.SetScores("2-2")
+	if winner := match.InferWinner(); winner != 0 {
+		t.Errorf("expected a tie, got %d\n", winner)
+	}
+
+	// editing the totals of a decoded match leaves its games behind
+	decoded := &challonge.Match{}
+	if err := json.Unmarshal([]byte(`{"id": 9, "player1_id": 1, "player2_id": 2, "scores_csv": "2-1"}`), decoded); err != nil {
+		t.Fatalf("unable to decode match.\nERR : %v\n", err)
+	}
+	decoded.PlayerOneScore, decoded.PlayerTwoScore = 0, 3
+	tournament := &challonge.Tournament{Type: "single elimination"}
+	if _, err := tournament.SubmitMatch(decoded); err == nil || !strings.Contains(err.Error(), "disagree") {
+		t.Errorf("expected the stale games to be refused, got %v\n", err)
+	}
+}
+
+func TestAttachments(t *testing.T) {
+	client := challonge.New(User, Key)
+	tournament, err := client.NewTournamentRequest("sample_tournament_1").WithMatches().Get()
+	if err != nil {
+		t.Fatalf("unable to retrieve tournament.\nERR : %v\n", err)
+	}
+	matches := tournament.GetMatches()
+	if len(matches) == 0 {
+		t.Skip("tournament has no matches")
+	}
+	attachment, err := matches[0].AddAttachment(challonge.AttachmentInput{
+		Description: "result screenshot",
+		File:        strings.NewReader("not really a png"),
+		FileName:    "result.png",
+	})
+	if err != nil {
+		t.Fatalf("unable to add attachment.\nERR : %v\n", err)
+	}
+	attachments, err := matches[0].GetAttachments()
+	if err != nil {
+		t.Fatalf("unable to list attachments.\nERR : %v\n", err)
+	}
+	t.Logf("Attachments : %v\n", attachments)
+	if err := matches[0].RemoveAttachment(attachment.Id); err != nil {
+		t.Fatalf("unable to remove attachment.\nERR : %v\n", err)
+	}
+}
+
+func TestDecodeMatch(t *testing.T) {
+	payload := `{"match": {"id": 23575258, "tournament_id": 1086875, "identifier": "A", "state": "complete",
+		"round": 1, "player1_id": 16543993, "player2_id": 16543997, "winner_id": 16543993, "loser_id": 16543997,
+		"player1_prereq_match_id": null, "player2_prereq_match_id": null, "player1_is_prereq_match_loser": false,
+		"prerequisite_match_ids_csv": "", "scores_csv": "3-1,1-3,3-2", "location": "Station 4",
+		"suggested_play_order": 1, "forfeited": null, "attachment_count": 2, "group_id": null,
+		"underway_at": "2015-01-19T16:57:17-05:00", "completed_at": "2015-01-19T17:02:20-05:00"}}`
+	item := challonge.MatchItem{}
+	if err := json.Unmarshal([]byte(payload), &item); err != nil {
+		t.Fatalf("unable to decode match.\nERR : %v\n", err)
+	}
+	match := item.Match
+	if match.LoserId != 16543997 || match.Location == nil || *match.Location != "Station 4" {
+		t.Errorf("unexpected match %+v\n", match)
+	}
+	if match.AttachmentCount == nil || *match.AttachmentCount != 2 || match.CompletedAt == nil {
+		t.Errorf("unexpected match %+v\n", match)
+	}
+	if len(match.Games) != 3 || match.PlayerOneScore != 2 || match.PlayerTwoScore != 1 {
+		t.Errorf("expected 2-1 over 3 games, got %d-%d over %d\n", match.PlayerOneScore, match.PlayerTwoScore, len(match.Games))
+	}
+}
+
+func TestDisqualify(t *testing.T) {
+	client := challonge.New(User, Key)
+	tournament, err := client.NewTournamentRequest("sample_tournament_1").WithParticipants().WithMatches().Get()
+	if err != nil {
+		t.Fatalf("unable to retrieve tournament.\nERR : %v\n", err)
+	}
+	if len(tournament.Participants) == 0 {
+		t.Skip("tournament has no participants")
+	}
+	result, err := tournament.Disqualify(tournament.Participants[0])
+	if err != nil {
+		t.Fatalf("unable to disqualify participant.\nERR : %v\n", err)
+	}
+	t.Logf("Forfeited : %v\n", result.Forfeited)
+	t.Logf("Advanced : %v\n", result.Advanced)
+}
+
+// doubleEliminationFixture is a four player double elimination bracket as
+// Challonge lays it out, including the grand final reset.
+func doubleEliminationFixture() *challonge.Tournament {
+	id := func(i int) *int { return &i }
+	return &challonge.Tournament{
+		Type:  "double elimination",
+		State: "underway",
+		Participants: []*challonge.Participant{
+			{Id: 101, Name: "one", Seed: 1}, {Id: 102, Name: "two", Seed: 2},
+			{Id: 103, Name: "three", Seed: 3}, {Id: 104, Name: "four", Seed: 4},
+		},
+		Matches: []*challonge.Match{
+			{Id: 1, Identifier: "A", Round: 1, State: "open", PlayerOneId: 101, PlayerTwoId: 104},
+			{Id: 2, Identifier: "B", Round: 1, State: "open", PlayerOneId: 102, PlayerTwoId: 103},
+			{Id: 3, Identifier: "C", Round: 2, State: "pending", PlayerOnePrereqMatch: id(1), PlayerTwoPrereqMatch: id(2)},
+			{Id: 4, Identifier: "D", Round: -1, State: "pending", PlayerOnePrereqMatch: id(1), PlayerOneIsPrereqMatchLoser: true,
+				PlayerTwoPrereqMatch: id(2), PlayerTwoIsPrereqMatchLoser: true},
+			{Id: 5, Identifier: "E", Round: -2, State: "pending", PlayerOnePrereqMatch: id(3), PlayerOneIsPrereqMatchLoser: true,
+				PlayerTwoPrereqMatch: id(4)},
+			{Id: 6, Identifier: "F", Round: 3, State: "pending", PlayerOnePrereqMatch: id(3), PlayerTwoPrereqMatch: id(5)},
+			{Id: 7, Identifier: "G", Round: 3, State: "pending", PlayerOnePrereqMatch: id(6), PlayerTwoPrereqMatch: id(6),
+				PlayerTwoIsPrereqMatchLoser: true},
+		},
+	}
+}
+
+func TestBracket(t *testing.T) {
+	bracket := challonge.NewBracket(doubleEliminationFixture())
+	if m := bracket.WinnerGoesTo(1); m == nil || m.Identifier != "C" {
+		t.Errorf("winner of A should go to C, got %v\n", m)
+	}
+	if m := bracket.LoserDropsTo(3); m == nil || m.Identifier != "E" {
+		t.Errorf("loser of C should drop to E, got %v\n", m)
+	}
+	if m := bracket.LoserDropsTo(5); m != nil {
+		t.Errorf("loser of E should be out, got %v\n", m)
+	}
+	if final := bracket.GrandFinal(); final == nil || final.Identifier != "F" {
+		t.Errorf("expected F to be the grand final, got %v\n", final)
+	}
+	if !bracket.IsGrandFinalReset(7) || bracket.IsGrandFinalReset(6) {
+		t.Errorf("expected G, and only G, to be the reset\n")
+	}
+	if !bracket.IsLosersBracket(4) || bracket.IsLosersBracket(3) {
+		t.Errorf("expected D, and not C, in the losers bracket\n")
+	}
+	path := bracket.PathToFinal(4)
+	identifiers := ""
+	for _, m := range path {
+		identifiers += m.Identifier
+	}
+	if identifiers != "DEF" {
+		t.Errorf("expected path DEF, got %q\n", identifiers)
+	}
+
+	// with two players the grand final takes the winner and loser of A
+	pair := &challonge.Tournament{Type: "double elimination", Participants: []*challonge.Participant{
+		{Id: 101, Name: "one", Seed: 1}, {Id: 102, Name: "two", Seed: 2},
+	}}
+	for _, modifier := range []string{"", "single match"} {
+		pair.GrandFinalsModifier = &modifier
+		matches, err := challonge.GenerateBracket(pair)
+		if err != nil {
+			t.Fatalf("unable to generate bracket.\nERR : %v\n", err)
+		}
+		pair.Matches = matches
+		bracket := challonge.NewBracket(pair)
+		if final := bracket.GrandFinal(); final == nil || final.Identifier != "B" {
+			t.Errorf("%q : expected B to be the grand final, got %v\n", modifier, final)
+		}
+		if bracket.IsGrandFinalReset(2) {
+			t.Errorf("%q : B is not a reset\n", modifier)
+		}
+		reset := bracket.GrandFinalReset()
+		if modifier == "" && (reset == nil || reset.Identifier != "C") {
+			t.Errorf("expected C to be the reset, got %v\n", reset)
+		} else if modifier != "" && reset != nil {
+			t.Errorf("expected no reset, got %v\n", reset)
+		}
+	}
+}
+
+// roundRobinFixture is a completed four player round robin where one and
+// two, and three and four, finish on the same number of wins.
+func roundRobinFixture() *challonge.Tournament {
+	match := func(id int, round int, one int, two int, scores string) *challonge.Match {
+		m := &challonge.Match{Id: id, Round: round, State: "complete", PlayerOneId: one, PlayerTwoId: two}
+		m.SetScores(scores)
+		m.WinnerId = m.InferWinner()
+		return m
+	}
+	return &challonge.Tournament{
+		Type:  "round robin",
+		State: "complete",
+		Participants: []*challonge.Participant{
+			{Id: 101, Name: "one", Seed: 1, Active: true}, {Id: 102, Name: "two", Seed: 2, Active: true},
+			{Id: 103, Name: "three", Seed: 3, Active: true}, {Id: 104, Name: "four", Seed: 4, Active: true},
+		},
+		Matches: []*challonge.Match{
+			match(1, 1, 101, 104, "1-2,1-2"),
+			match(2, 1, 102, 103, "2-0"),
+			match(3, 2, 101, 103, "2-1"),
+			match(4, 2, 104, 102, "0-2"),
+			match(5, 3, 101, 102, "2-1"),
+			match(6, 3, 103, 104, "2-0"),
+		},
+	}
+}
+
+func TestStandings(t *testing.T) {
+	tournament := roundRobinFixture()
+	standings := tournament.Standings(nil)
+	expected := []string{"one", "two", "three", "four"}
+	for i, s := range standings {
+		if s.Participant.Name != expected[i] || s.Rank != i+1 {
+			t.Errorf("rank %d : expected %s, got %s (rank %d)\n", i+1, expected[i], s.Participant.Name, s.Rank)
+		}
+	}
+	if standings[0].Points != 2 || standings[0].MatchLosses != 1 {
+		t.Errorf("unexpected standing for one : %+v\n", standings[0])
+	}
+
+	// without head to head, two's better game record puts them first
+	opts := challonge.DefaultStandingsOptions(tournament)
+	opts.TieBreaks = []challonge.TieBreak{challonge.TieBreakPointsDifference}
+	standings = tournament.Standings(opts)
+	if standings[0].Participant.Name != "two" {
+		t.Errorf("expected two first on points difference, got %s\n", standings[0].Participant.Name)
+	}
+}
+
+func TestStandingsSwissByes(t *testing.T) {
+	tournament := &challonge.Tournament{Type: "swiss", State: "underway"}
+	for i := 1; i <= 5; i++ {
+		tournament.Participants = append(tournament.Participants,
+			&challonge.Participant{Id: 100 + i, Name: fmt.Sprintf("player %d", i), Seed: i, Active: true})
+	}
+	// round one is under way: 1 beat 2, 3 and 4 haven't played yet, 5 has the bye
+	tournament.Matches = []*challonge.Match{
+		{Id: 1, Round: 1, State: "complete", PlayerOneId: 101, PlayerTwoId: 102, WinnerId: 101},
+		{Id: 2, Round: 1, State: "open", PlayerOneId: 103, PlayerTwoId: 104},
+	}
+	for _, s := range tournament.Standings(nil) {
+		byes, points := 0, 0.0
+		switch s.Participant.Id {
+		case 101:
+			points = 1
+		case 105:
+			byes, points = 1, 1
+		}
+		if s.Byes != byes || s.Points != points {
+			t.Errorf("player %d : expected %d byes and %g points, got %d and %g\n", s.Participant.Id, byes, points, s.Byes, s.Points)
+		}
+	}
+}
+
+func TestParticipantStatsIdempotent(t *testing.T) {
+	tournament := roundRobinFixture()
+	first := tournament.ParticipantStats()
+	for i := 0; i < 3; i++ {
+		tournament.GetMatches()
+		tournament.GetOpenMatches()
+		tournament.GetMatch(1)
+	}
+	second := tournament.ParticipantStats()
+	for id, s := range first {
+		if *second[id] != *s {
+			t.Errorf("stats of %d changed from %+v to %+v\n", id, s, second[id])
+		}
+	}
+	expected := map[int]challonge.ParticipantStats{
+		101: {ParticipantId: 101, Wins: 2, Losses: 1, TotalScore: 4},
+		102: {ParticipantId: 102, Wins: 2, Losses: 1, TotalScore: 5},
+		103: {ParticipantId: 103, Wins: 1, Losses: 2, TotalScore: 3},
+		104: {ParticipantId: 104, Wins: 1, Losses: 2, TotalScore: 2},
+	}
+	for id, e := range expected {
+		if *first[id] != e {
+			t.Errorf("participant %d : expected %+v, got %+v\n", id, e, first[id])
+		}
+	}
+
+	// matches without players yet must not break anything
+	bracket := doubleEliminationFixture()
+	bracket.GetMatches()
+	if s := bracket.GetStats(101); s.Wins != 0 || s.Losses != 0 {
+		t.Errorf("expected no stats before any match, got %+v\n", s)
+	}
+}
+
+func TestDiffMatchesById(t *testing.T) {
+	before := doubleEliminationFixture().Matches
+	after := doubleEliminationFixture().Matches
+	// reversed order must not matter
+	for i, j := 0, len(after)-1; i < j; i, j = i+1, j-1 {
+		after[i], after[j] = after[j], after[i]
+	}
+	if diff := challonge.DiffMatchesById(before, after); !diff.IsEmpty() {
+		t.Fatalf("expected no changes, got %+v\n", diff)
+	}
+
+	for _, m := range after {
+		if m.Id == 1 {
+			m.State = "complete"
+			m.SetScores("2-0")
+			m.WinnerId = 101
+		}
+		if m.Id == 3 {
+			m.PlayerOneId = 101
+		}
+	}
+	after = append(after[:1], after[2:]...) // drops match 6
+	diff := challonge.DiffMatchesById(before, after)
+	if len(diff.Removed) != 1 || diff.Removed[0].Id != 6 {
+		t.Errorf("expected match 6 to be removed, got %v\n", diff.Removed)
+	}
+	if len(diff.Changed) != 2 {
+		t.Fatalf("expected 2 changed matches, got %d\n", len(diff.Changed))
+	}
+	for _, c := range diff.Changed {
+		switch c.New.Id {
+		case 1:
+			if !c.Has(challonge.MatchFieldState) || !c.Has(challonge.MatchFieldScores) || !c.Has(challonge.MatchFieldWinner) {
+				t.Errorf("unexpected changes for match 1 : %+v\n", c.Changes)
+			}
+		case 3:
+			if len(c.Changes) != 1 || c.Changes[0].Field != challonge.MatchFieldPlayerOne || c.Changes[0].New != "101" {
+				t.Errorf("unexpected changes for match 3 : %+v\n", c.Changes)
+			}
+		}
+	}
+}
+
+func TestDiffTournaments(t *testing.T) {
+	before := doubleEliminationFixture()
+	before.State = "pending"
+	after := doubleEliminationFixture()
+	after.Name = "renamed"
+	after.Participants[1].CheckedIn = true
+	after.Participants = append(after.Participants, &challonge.Participant{Id: 105, Name: "five", Seed: 5})
+	for _, m := range after.Matches {
+		switch m.Id {
+		case 1:
+			m.State = "complete"
+			m.SetScores("2-1")
+			m.WinnerId = 101
+		case 3:
+			m.PlayerOneId = 101
+		case 4:
+			m.PlayerOneId, m.PlayerTwoId = 104, 103
+		}
+	}
+
+	expected := []challonge.EventType{
+		challonge.EventTournamentSettingChanged,
+		challonge.EventParticipantCheckedIn,
+		challonge.EventParticipantAdded,
+		challonge.EventTournamentStarted,
+		challonge.EventMatchCompleted,
+		challonge.EventMatchPlayersChanged,
+		challonge.EventMatchPlayersChanged,
+	}
+	events := challonge.DiffTournaments(before, after)
+	if len(events) != len(expected) {
+		t.Fatalf("expected %d events, got %d : %+v\n", len(expected), len(events), events)
+	}
+	for i, e := range events {
+		if e.Type != expected[i] {
+			t.Errorf("event %d : expected %s, got %s\n", i, expected[i], e.Type)
+		}
+	}
+	if events[4].Match.Id != 1 || events[5].Match.Id != 3 {
+		t.Errorf("unexpected matches in events : %v, %v\n", events[4].Match, events[5].Match)
+	}
+	// both players of match 4 arrive together, as one event
+	if e := events[6]; e.Match.Id != 4 || e.Field != "players" || e.Old != "0,0" || e.New != "104,103" {
+		t.Errorf("unexpected players event : %+v\n", e)
+	}
+
+	// the organiser corrects the winner of match 1 without touching the scores
+	corrected := *after
+	corrected.Matches = append([]*challonge.Match{}, after.Matches...)
+	fixed := *after.Matches[0]
+	fixed.WinnerId = 104
+	corrected.Matches[0] = &fixed
+	events = challonge.DiffTournaments(after, &corrected)
+	if len(events) != 1 || events[0].Type != challonge.EventMatchWinnerChanged || events[0].Old != "101" || events[0].New != "104" {
+		t.Errorf("expected a single winner change, got %+v\n", events)
+	}
+}
+
+func TestWatch(t *testing.T) {
+	client := challonge.New(User, Key)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	opts := &challonge.WatchOptions{
+		Interval: 2 * time.Second,
+		Jitter:   0.2,
+		OnError:  func(err error) { t.Logf("poll failed : %v\n", err) },
+	}
+	events, stopped := client.Watch(ctx, "sample_tournament_1", opts)
+	for e := range events {
+		t.Logf("Event : %s %s %s -> %s\n", e.Type, e.Field, e.Old, e.New)
+	}
+	if err := stopped(); err != context.DeadlineExceeded {
+		t.Errorf("expected the watch to stop at the deadline.\nERR : %v\n", err)
+	}
+}
+
+func TestWebhookHandler(t *testing.T) {
+	handler := challonge.NewWebhookHandler("secret")
+	completed := 0
+	handler.On(challonge.EventMatchCompleted, func(e challonge.Event) {
+		completed++
+		if e.Match == nil || e.Match.Id != 42 || e.Match.WinnerId != 101 || e.Match.PlayerOne == nil {
+			t.Errorf("unexpected match %+v\n", e.Match)
+		}
+	})
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	body := []byte(`{"event": "match.completed", "id": "delivery-1",
+		"tournament": {"id": 1, "name": "weekly", "participants": [{"participant": {"id": 101, "display_name": "one"}}]},
+		"match": {"id": 42, "state": "complete", "player1_id": 101, "player2_id": 102, "winner_id": 101, "scores_csv": "2-0"}}`)
+	post := func(signature string) int {
+		req, _ := http.NewRequest("POST", server.URL, bytes.NewReader(body))
+		req.Header.Set("X-Challonge-Signature", signature)
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("unable to post webhook.\nERR : %v\n", err)
+		}
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	if status := post("deadbeef"); status != http.StatusUnauthorized {
+		t.Errorf("expected 401 for a bad signature, got %d\n", status)
+	}
+	signature := challonge.SignWebhook("secret", body)
+	for i := 0; i < 2; i++ {
+		if status := post(signature); status != http.StatusOK {
+			t.Errorf("expected 200, got %d\n", status)
+		}
+	}
+	if completed != 1 {
+		t.Errorf("expected the redelivery to be ignored, handler ran %d times\n", completed)
+	}
+
+	// an empty secret would let anyone sign deliveries
+	unsigned := httptest.NewServer(challonge.NewWebhookHandler(""))
+	defer unsigned.Close()
+	req, _ := http.NewRequest("POST", unsigned.URL, bytes.NewReader(body))
+	req.Header.Set("X-Challonge-Signature", challonge.SignWebhook("", body))
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("unable to post webhook.\nERR : %v\n", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusInternalServerError {
+		t.Errorf("expected 500 without a secret, got %d\n", resp.StatusCode)
+	}
+}
+
+func TestGenerateBracket(t *testing.T) {
+	// four player double elimination, compared with the hand written fixture
+	fixture := doubleEliminationFixture()
+	generated, err := challonge.GenerateBracket(fixture)
+	if err != nil {
+		t.Fatalf("unable to generate bracket.\nERR : %v\n", err)
+	}
+	if len(generated) != len(fixture.Matches) {
+		t.Fatalf("expected %d matches, got %d\n", len(fixture.Matches), len(generated))
+	}
+	prereq := func(p *int) int {
+		if p == nil {
+			return 0
+		}
+		return *p
+	}
+	for i, e := range fixture.Matches {
+		g := generated[i]
+		if g.Id != e.Id || g.Identifier != e.Identifier || g.Round != e.Round || g.State != e.State ||
+			g.PlayerOneId != e.PlayerOneId || g.PlayerTwoId != e.PlayerTwoId ||
+			prereq(g.PlayerOnePrereqMatch) != prereq(e.PlayerOnePrereqMatch) ||
+			prereq(g.PlayerTwoPrereqMatch) != prereq(e.PlayerTwoPrereqMatch) ||
+			g.PlayerOneIsPrereqMatchLoser != e.PlayerOneIsPrereqMatchLoser ||
+			g.PlayerTwoIsPrereqMatchLoser != e.PlayerTwoIsPrereqMatchLoser {
+			t.Errorf("match %s : expected %+v, got %+v\n", e.Identifier, e, g)
+		}
+	}
+
+	// six players, seeds one and two get byes
+	single := &challonge.Tournament{Type: "single elimination"}
+	for i := 1; i <= 6; i++ {
+		single.Participants = append(single.Participants, &challonge.Participant{Id: 100 + i, Seed: i})
+	}
+	generated, err = challonge.GenerateBracket(single)
+	if err != nil {
+		t.Fatalf("unable to generate bracket.\nERR : %v\n", err)
+	}
+	expected := []struct {
+		players [2]int
+		prereqs [2]int
+		round   int
+	}{
+		{[2]int{104, 105}, [2]int{0, 0}, 1},
+		{[2]int{103, 106}, [2]int{0, 0}, 1},
+		{[2]int{101, 0}, [2]int{0, 1}, 2},
+		{[2]int{102, 0}, [2]int{0, 2}, 2},
+		{[2]int{0, 0}, [2]int{3, 4}, 3},
+	}
+	if len(generated) != len(expected) {
+		t.Fatalf("expected %d matches, got %d\n", len(expected), len(generated))
+	}
+	for i, e := range expected {
+		g := generated[i]
+		if g.PlayerOneId != e.players[0] || g.PlayerTwoId != e.players[1] || g.Round != e.round ||
+			prereq(g.PlayerOnePrereqMatch) != e.prereqs[0] || prereq(g.PlayerTwoPrereqMatch) != e.prereqs[1] {
+			t.Errorf("match %d : expected %+v, got %+v\n", i+1, e, g)
+		}
+	}
+
+	// eight player double elimination must form a consistent bracket
+	double := &challonge.Tournament{Type: "double elimination"}
+	for i := 1; i <= 8; i++ {
+		double.Participants = append(double.Participants, &challonge.Participant{Id: 100 + i, Seed: i})
+	}
+	double.Matches, err = challonge.GenerateBracket(double)
+	if err != nil {
+		t.Fatalf("unable to generate bracket.\nERR : %v\n", err)
+	}
+	if len(double.Matches) != 2*8-1 {
+		t.Errorf("expected %d matches, got %d\n", 2*8-1, len(double.Matches))
+	}
+	bracket := challonge.NewBracket(double)
+	if bracket.GrandFinalReset() == nil {
+		t.Errorf("expected a grand final reset\n")
+	}
+	for _, m := range double.Matches {
+		if bracket.PathToFinal(m.Id) == nil && !bracket.IsGrandFinalReset(m.Id) {
+			t.Errorf("match %s does not lead to the grand final\n", m.Identifier)
+		}
+	}
+}
+
+// bracketConfig is a bracket recorded into testdata/brackets by
+// TestRecordBracketFixtures.
+type bracketConfig struct {
+	name     string
+	tType    string
+	players  int
+	settings map[string]string
+}
+
+func bracketConfigs() []bracketConfig {
+	configs := make([]bracketConfig, 0)
+	for _, n := range []int{4, 6, 8} {
+		for _, third := range []bool{false, true} {
+			name := fmt.Sprintf("single_%d", n)
+			if third {
+				name += "_third_place"
+			}
+			configs = append(configs, bracketConfig{name, "single", n, map[string]string{"tournament[hold_third_place_match]": fmt.Sprint(third)}})
+		}
+		for _, modifier := range []string{"", "single match", "skip"} {
+			name := fmt.Sprintf("double_%d", n)
+			if modifier != "" {
+				name += "_" + strings.ReplaceAll(modifier, " ", "_")
+			}
+			configs = append(configs, bracketConfig{name, "double", n, map[string]string{"tournament[grand_finals_modifier]": modifier}})
+		}
+	}
+	return configs
+}
+
+// TestRecordBracketFixtures creates, starts and deletes a tournament for
+// every bracket configuration, saving what Challonge generated. It only
+// runs with CHALLONGE_RECORD=1 and credentials.
+func TestRecordBracketFixtures(t *testing.T) {
+	if os.Getenv("CHALLONGE_RECORD") == "" {
+		t.Skip("set CHALLONGE_RECORD=1 to record bracket fixtures")
+	}
+	client := challonge.New(User, Key)
+	stamp := strconv.FormatInt(time.Now().Unix(), 36)
+	for _, c := range bracketConfigs() {
+		url := "gochallonge_" + c.name + "_" + stamp
+		tournament, err := client.CreateTournament(url, url, "", false, c.tType, "bracket fixture")
+		if err != nil {
+			t.Fatalf("unable to create tournament.\nERR : %v\n", err)
+		}
+		func() {
+			defer tournament.Destroy()
+			if err := tournament.UpdateSettings(c.settings); err != nil {
+				t.Fatalf("unable to update settings.\nERR : %v\n", err)
+			}
+			entries := make([]challonge.ParticipantInput, 0, c.players)
+			for i := 1; i <= c.players; i++ {
+				entries = append(entries, challonge.ParticipantInput{Name: fmt.Sprintf("player %d", i), Seed: i})
+			}
+			if result := tournament.BulkAddParticipants(entries); len(result.Errors) > 0 {
+				t.Fatalf("unable to add participants.\nERR : %v\n", result.Errors[0])
+			}
+			if err := tournament.Start(); err != nil {
+				t.Fatalf("unable to start tournament.\nERR : %v\n", err)
+			}
+			body, err := client.RawTournament(tournament.GetUrl())
+			if err != nil {
+				t.Fatalf("unable to retrieve tournament.\nERR : %v\n", err)
+			}
+			if err := os.WriteFile(filepath.Join("testdata", "brackets", c.name+".json"), body, 0644); err != nil {
+				t.Fatalf("unable to save fixture.\nERR : %v\n", err)
+			}
+		}()
+	}
+}
+
+// TestGenerateBracketFixtures compares GenerateBracket with the brackets
+// Challonge generated, as recorded in testdata/brackets.
+func TestGenerateBracketFixtures(t *testing.T) {
+	files, _ := filepath.Glob(filepath.Join("testdata", "brackets", "*.json"))
+	if len(files) == 0 {
+		t.Skip("no recorded brackets, see testdata/brackets/README.md")
+	}
+	for _, file := range files {
+		body, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatalf("unable to read fixture.\nERR : %v\n", err)
+		}
+		response := struct {
+			Tournament *challonge.Tournament `json:"tournament"`
+		}{}
+		if err := json.Unmarshal(body, &response); err != nil || response.Tournament == nil {
+			t.Fatalf("unable to decode %s.\nERR : %v\n", file, err)
+		}
+		recorded := response.Tournament
+		input := *recorded
+		input.Participants = nil
+		for i := range recorded.ParticipantItems {
+			input.Participants = append(input.Participants, &recorded.ParticipantItems[i].Participant)
+		}
+		generated, err := challonge.GenerateBracket(&input)
+		if err != nil {
+			t.Errorf("%s : unable to generate bracket.\nERR : %v\n", file, err)
+			continue
+		}
+		if len(generated) != len(recorded.MatchItems) {
+			t.Errorf("%s : expected %d matches, got %d\n", file, len(recorded.MatchItems), len(generated))
+			continue
+		}
+
+		// prereqs are compared by identifier, match ids differ
+		identifiers := func(matches []*challonge.Match) (map[int]string, map[string]*challonge.Match) {
+			byId, byIdentifier := map[int]string{}, map[string]*challonge.Match{}
+			for _, m := range matches {
+				byId[m.Id] = m.Identifier
+				byIdentifier[m.Identifier] = m
+			}
+			return byId, byIdentifier
+		}
+		recordedMatches := make([]*challonge.Match, 0, len(recorded.MatchItems))
+		for _, item := range recorded.MatchItems {
+			recordedMatches = append(recordedMatches, item.Match)
+		}
+		recordedIds, _ := identifiers(recordedMatches)
+		generatedIds, generatedMatches := identifiers(generated)
+		prereq := func(ids map[int]string, p *int) string {
+			if p == nil {
+				return ""
+			}
+			return ids[*p]
+		}
+		for _, e := range recordedMatches {
+			g := generatedMatches[e.Identifier]
+			if g == nil {
+				t.Errorf("%s : match %s was not generated\n", file, e.Identifier)
+				continue
+			}
+			if g.Round != e.Round || g.PlayerOneId != e.PlayerOneId || g.PlayerTwoId != e.PlayerTwoId ||
+				prereq(generatedIds, g.PlayerOnePrereqMatch) != prereq(recordedIds, e.PlayerOnePrereqMatch) ||
+				prereq(generatedIds, g.PlayerTwoPrereqMatch) != prereq(recordedIds, e.PlayerTwoPrereqMatch) ||
+				g.PlayerOneIsPrereqMatchLoser != e.PlayerOneIsPrereqMatchLoser ||
+				g.PlayerTwoIsPrereqMatchLoser != e.PlayerTwoIsPrereqMatchLoser {
+				t.Errorf("%s : match %s differs\nrecorded  : round %d, %d vs %d, prereqs %s/%s, losers %v/%v\ngenerated : round %d, %d vs %d, prereqs %s/%s, losers %v/%v\n",
+					file, e.Identifier,
+					e.Round, e.PlayerOneId, e.PlayerTwoId, prereq(recordedIds, e.PlayerOnePrereqMatch), prereq(recordedIds, e.PlayerTwoPrereqMatch),
+					e.PlayerOneIsPrereqMatchLoser, e.PlayerTwoIsPrereqMatchLoser,
+					g.Round, g.PlayerOneId, g.PlayerTwoId, prereq(generatedIds, g.PlayerOnePrereqMatch), prereq(generatedIds, g.PlayerTwoPrereqMatch),
+					g.PlayerOneIsPrereqMatchLoser, g.PlayerTwoIsPrereqMatchLoser)
+			}
+		}
+	}
+}
+
+func TestPairSwiss(t *testing.T) {
+	tournament := &challonge.Tournament{Type: "swiss", State: "underway"}
+	teams := map[int]string{101: "red", 102: "blue", 103: "red", 104: "blue", 105: "green"}
+	for i := 1; i <= 5; i++ {
+		tournament.Participants = append(tournament.Participants,
+			&challonge.Participant{Id: 100 + i, Name: fmt.Sprintf("player %d", i), Seed: i, Active: true})
+	}
+	// round one: 1 beat 3, 2 beat 4, 5 had the bye
+	for i, pair := range [][2]int{{101, 103}, {102, 104}} {
+		m := &challonge.Match{Id: i + 1, Round: 1, State: "complete", PlayerOneId: pair[0], PlayerTwoId: pair[1], WinnerId: pair[0]}
+		m.SetScores("2-0")
+		tournament.Matches = append(tournament.Matches, m)
+	}
+
+	round, err := tournament.PairSwiss(&challonge.SwissOptions{
+		Team: func(p *challonge.Participant) string { return teams[p.Id] },
+	})
+	if err != nil {
+		t.Fatalf("unable to pair round.\nERR : %v\n", err)
+	}
+	if round.Round != 2 {
+		t.Errorf("expected round 2, got %d\n", round.Round)
+	}
+	seen := map[int]bool{}
+	byes := 0
+	for _, p := range round.Pairings {
+		t.Logf("%v vs %v : %v\n", p.PlayerOne.Name, p.PlayerTwo, p.Reasons)
+		if p.PlayerTwo == nil {
+			byes++
+			if p.PlayerOne.Id == 105 {
+				t.Errorf("player 5 already had a bye\n")
+			}
+			seen[p.PlayerOne.Id] = true
+			continue
+		}
+		one, two := p.PlayerOne.Id, p.PlayerTwo.Id
+		if teams[one] == teams[two] {
+			t.Errorf("%d and %d are on the same team\n", one, two)
+		}
+		if (one == 101 && two == 103) || (one == 102 && two == 104) {
+			t.Errorf("%d and %d already played\n", one, two)
+		}
+		if len(p.Reasons) == 0 {
+			t.Errorf("pairing of %d and %d is not explained\n", one, two)
+		}
+		seen[one], seen[two] = true, true
+	}
+	if byes != 1 || len(seen) != 5 {
+		t.Errorf("expected every player once and one bye, got %v\n", seen)
+	}
+}
+
+func TestPairSwissFewestRematches(t *testing.T) {
+	// six players level on points: 5 has met everyone, 0 has also met 3
+	tournament := &challonge.Tournament{Type: "swiss", State: "underway"}
+	for i := 0; i < 6; i++ {
+		tournament.Participants = append(tournament.Participants,
+			&challonge.Participant{Id: 100 + i, Name: fmt.Sprintf("p%d", i), Seed: i + 1, Active: true})
+	}
+	played := [][2]int{{105, 100}, {105, 101}, {105, 102}, {105, 103}, {105, 104}, {100, 103}}
+	for i, pair := range played {
+		tournament.Matches = append(tournament.Matches,
+			&challonge.Match{Id: i + 1, Round: i + 1, State: "complete", PlayerOneId: pair[0], PlayerTwoId: pair[1]})
+	}
+	round, err := tournament.PairSwiss(&challonge.SwissOptions{Standings: &challonge.StandingsOptions{}})
+	if err != nil {
+		t.Fatalf("unable to pair round.\nERR : %v\n", err)
+	}
+	met := map[[2]int]bool{}
+	for _, pair := range played {
+		met[pair], met[[2]int{pair[1], pair[0]}] = true, true
+	}
+	rematches := 0
+	for _, p := range round.Pairings {
+		t.Logf("%v vs %v : %v\n", p.PlayerOne.Name, p.PlayerTwo.Name, p.Reasons)
+		if met[[2]int{p.PlayerOne.Id, p.PlayerTwo.Id}] {
+			rematches++
+			if reason := p.Reasons[len(p.Reasons)-1]; reason != "rematch, every pairing needs at least 1" {
+				t.Errorf("unexpected reason for the rematch : %q\n", reason)
+			}
+		}
+	}
+	if rematches != 1 {
+		t.Errorf("expected a single rematch, got %d\n", rematches)
+	}
+}
+
+func TestGenerateRoundRobin(t *testing.T) {
+	tournament := &challonge.Tournament{Type: "round robin"}
+	for i := 1; i <= 5; i++ {
+		tournament.Participants = append(tournament.Participants, &challonge.Participant{Id: 100 + i, Seed: i})
+	}
+	matches, err := challonge.GenerateRoundRobin(tournament, nil)
+	if err != nil {
+		t.Fatalf("unable to generate schedule.\nERR : %v\n", err)
+	}
+	if len(matches) != 10 {
+		t.Fatalf("expected 10 matches, got %d\n", len(matches))
+	}
+	pairs := map[[2]int]int{}
+	perRound := map[int]map[int]bool{}
+	home := map[int]int{}
+	for _, m := range matches {
+		one, two := min(m.PlayerOneId, m.PlayerTwoId), max(m.PlayerOneId, m.PlayerTwoId)
+		pairs[[2]int{one, two}]++
+		if perRound[m.Round] == nil {
+			perRound[m.Round] = map[int]bool{}
+		}
+		if perRound[m.Round][one] || perRound[m.Round][two] {
+			t.Errorf("a player plays twice in round %d\n", m.Round)
+		}
+		perRound[m.Round][one], perRound[m.Round][two] = true, true
+		home[m.PlayerOneId]++
+	}
+	if len(pairs) != 10 || len(perRound) != 5 {
+		t.Errorf("expected 10 distinct pairings over 5 rounds, got %d over %d\n", len(pairs), len(perRound))
+	}
+	for round, players := range perRound {
+		if len(players) != 4 {
+			t.Errorf("expected one player to sit out round %d, %d played\n", round, len(players))
+		}
+	}
+	for id, count := range home {
+		if count < 1 || count > 3 {
+			t.Errorf("player %d is player one %d times out of 4\n", id, count)
+		}
+	}
+
+	matches, err = challonge.GenerateRoundRobin(tournament, &challonge.RoundRobinOptions{Double: true})
+	if err != nil {
+		t.Fatalf("unable to generate schedule.\nERR : %v\n", err)
+	}
+	if len(matches) != 20 || matches[19].Round != 10 {
+		t.Errorf("expected 20 matches over 10 rounds, got %d\n", len(matches))
+	}
+}
+
+func TestSimulate(t *testing.T) {
+	tournament := doubleEliminationFixture()
+	opts := &challonge.SimulationOptions{
+		Runs:    4000,
+		Seed:    7,
+		Ratings: map[int]float64{101: 1800, 102: 1600, 103: 1500, 104: 1300},
+	}
+	result, err := challonge.Simulate(tournament, opts)
+	if err != nil {
+		t.Fatalf("unable to simulate.\nERR : %v\n", err)
+	}
+	again, _ := challonge.Simulate(tournament, opts)
+	total := 0.0
+	for id, placements := range result.Placements {
+		for rank, p := range placements {
+			if again.Placements[id][rank] != p {
+				t.Errorf("simulation with the same seed differs for %d at rank %d\n", id, rank)
+			}
+		}
+		total += placements[1]
+	}
+	if math.Abs(total-1) > 1e-9 {
+		t.Errorf("win probabilities add up to %f\n", total)
+	}
+	if result.WinProbability(101) <= result.WinProbability(104) {
+		t.Errorf("the highest rated player should be more likely to win than the lowest\n")
+	}
+	first := result.MatchWins[1]
+	if math.Abs(first[101]+first[104]-1) > 1e-9 || first[101] < 0.8 {
+		t.Errorf("unexpected odds for match A : %v\n", first)
+	}
+	for id := range result.Placements {
+		ranks := result.Placements[id]
+		if ranks[2] > 0 && ranks[1]+ranks[2]+ranks[3]+ranks[4] < 0.999 {
+			t.Errorf("player %d has placements outside 1-4 : %v\n", id, ranks)
+		}
+	}
+
+	// a decided match keeps its result
+	tournament.Matches[0].State = "complete"
+	tournament.Matches[0].WinnerId = 104
+	result, _ = challonge.Simulate(tournament, opts)
+	if _, ok := result.MatchWins[1]; ok {
+		t.Errorf("completed match A should not be simulated\n")
+	}
+	if odds := result.MatchWins[4]; odds[104] != 0 {
+		t.Errorf("winner of A can't play in the losers bracket : %v\n", odds)
+	}
+}
@@ -0,0 +1,167 @@
+package challonge
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// CloneOptions overrides parts of the source tournament when cloning.
+// Empty fields keep the value of the source tournament.
+type CloneOptions struct {
+	Name      string
+	Url       string // generated from the source url when empty
+	Subdomain string
+
+	// WithParticipants copies the roster over with the same seeds.
+	WithParticipants bool
+
+	// Params sets raw "tournament[...]" parameters, after all other settings.
+	Params map[string]string
+}
+
+// CloneFailure describes a part of the source that could not be copied.
+type CloneFailure struct {
+	Item string
+	Err  error
+}
+
+// CloneReport lists what CloneTournament copied and what failed.
+type CloneReport struct {
+	Source     *Tournament
+	Tournament *Tournament
+
+	Settings     []string
+	Participants []*Participant
+	Failures     []CloneFailure
+}
+
+// CloneTournament reads the tournament identified by src and creates a new
+// tournament with the same settings. An error is only returned when the
+// source could not be read or the new tournament could not be created;
+// failures on individual participants are recorded in the report.
+func (c *Client) CloneTournament(src string, overrides *CloneOptions) (*CloneReport, error) {
+	if overrides == nil {
+		overrides = &CloneOptions{}
+	}
+	source, err := c.NewTournamentRequest(src).WithParticipants().GetContext(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	report := &CloneReport{Source: source}
+
+	v := source.settingsParams()
+	name := source.Name
+	if overrides.Name != "" {
+		name = overrides.Name
+	}
+	subUrl := overrides.Url
+	if subUrl == "" {
+		subUrl = fmt.Sprintf("%s_%s", source.Url, strconv.FormatInt(time.Now().Unix(), 36))
+	}
+	subdomain := source.SubDomain
+	if overrides.Subdomain != "" {
+		subdomain = overrides.Subdomain
+	}
+	v.Set("tournament[name]", name)
+	v.Set("tournament[url]", subUrl)
+	if subdomain != "" {
+		v.Set("tournament[subdomain]", subdomain)
+	}
+	for k, value := range overrides.Params {
+		v.Set(k, value)
+	}
+	for k := range v {
+		report.Settings = append(report.Settings, k)
+	}
+	sort.Strings(report.Settings)
+
+	response := &APIResponse{}
+	if err := doRequest(context.Background(), "POST", c.buildUrl("tournaments", v), "", nil, response); err != nil {
+		return nil, fmt.Errorf("unable to create tournament: %v", err)
+	}
+	if response.hasErrors() {
+		return nil, fmt.Errorf("unable to create tournament: %q", response.Errors[0])
+	}
+	report.Tournament = response.getTournament()
+	report.Tournament.SubUrl = report.Tournament.GetUrl()
+
+	if !overrides.WithParticipants {
+		return report, nil
+	}
+	participants := make([]*Participant, len(source.Participants))
+	copy(participants, source.Participants)
+	sort.SliceStable(participants, func(i, j int) bool {
+		return participants[i].Seed < participants[j].Seed
+	})
+	entries := make([]ParticipantInput, 0, len(participants))
+	for _, p := range participants {
+		entries = append(entries, ParticipantInput{Name: p.Name, Seed: p.Seed, Misc: p.Misc})
+	}
+	// bulk_add reports failures instead of exiting on network errors like AddParticipant
+	added := report.Tournament.BulkAddParticipants(entries)
+	report.Participants = added.Participants
+	for _, e := range added.Errors {
+		report.Failures = append(report.Failures, CloneFailure{Item: "participant " + e.Entry.Name, Err: e.Err})
+	}
+	return report, nil
+}
+
+/** returns the "tournament[...]" parameters needed to recreate t */
+func (t *Tournament) settingsParams() url.Values {
+	v := url.Values{}
+	set := func(key string, value string) {
+		if value != "" {
+			v.Set("tournament["+key+"]", value)
+		}
+	}
+	setBool := func(key string, value bool) {
+		v.Set("tournament["+key+"]", strconv.FormatBool(value))
+	}
+
+	set("tournament_type", t.Type)
+	if t.DescriptionSource != "" {
+		set("description", t.DescriptionSource)
+	} else {
+		set("description", t.Description)
+	}
+	set("game_name", t.GameName)
+	setBool("open_signup", t.OpenSignup)
+	setBool("private", t.Private)
+	setBool("teams", t.Teams)
+	setBool("hold_third_place_match", t.HoldThirdPlaceMatch)
+	if t.GrandFinalsModifier != nil {
+		set("grand_finals_modifier", *t.GrandFinalsModifier)
+	}
+	set("pts_for_match_win", t.PtsForMatchWin)
+	set("pts_for_match_tie", t.PtsForMatchTie)
+	set("pts_for_game_win", t.PtsForGameWin)
+	set("pts_for_game_tie", t.PtsForGameTie)
+	set("pts_for_bye", t.PtsForBye)
+	if t.SwissRounds > 0 {
+		set("swiss_rounds", strconv.Itoa(t.SwissRounds))
+	}
+	set("ranked_by", t.RankedBy)
+	set("rr_pts_for_match_win", t.RrPtsForMatchWin)
+	set("rr_pts_for_match_tie", t.RrPtsForMatchTie)
+	set("rr_pts_for_game_win", t.RrPtsForGameWin)
+	set("rr_pts_for_game_tie", t.RrPtsForGameTie)
+	setBool("accept_attachments", t.AcceptAttachments)
+	setBool("hide_forum", t.HideForum)
+	setBool("show_rounds", t.ShowRounds)
+	setBool("hide_seeds", t.HideSeeds)
+	setBool("quick_advance", t.QuickAdvance)
+	setBool("sequential_pairings", t.SequentialPairings)
+	setBool("notify_users_when_matches_open", t.NotifyUsersWhenMatchesOpen)
+	setBool("notify_users_when_the_tournament_ends", t.NotifyUsersWhenTheTournamentEnds)
+	if t.SignupCap != nil {
+		set("signup_cap", strconv.Itoa(*t.SignupCap))
+	}
+	if t.CheckInDuration != nil {
+		set("check_in_duration", strconv.Itoa(*t.CheckInDuration))
+	}
+	return v
+}
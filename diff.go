@@ -0,0 +1,101 @@
+package challonge
+
+import "strconv"
+
+// MatchField names a match field compared by DiffMatchesById.
+type MatchField string
+
+const (
+	MatchFieldState     MatchField = "state"
+	MatchFieldScores    MatchField = "scores"
+	MatchFieldWinner    MatchField = "winner"
+	MatchFieldPlayerOne MatchField = "player1"
+	MatchFieldPlayerTwo MatchField = "player2"
+	MatchFieldUnderway  MatchField = "underway"
+)
+
+// FieldChange is a single field that differs between two versions of a match.
+type FieldChange struct {
+	Field MatchField
+	Old   string
+	New   string
+}
+
+// MatchChange holds both versions of a changed match and what changed.
+type MatchChange struct {
+	Old     *Match
+	New     *Match
+	Changes []FieldChange
+}
+
+/** reports whether field is among the changes */
+func (c *MatchChange) Has(field MatchField) bool {
+	for _, f := range c.Changes {
+		if f.Field == field {
+			return true
+		}
+	}
+	return false
+}
+
+// MatchDiff is the result of DiffMatchesById. Added and Changed follow the
+// order of the new matches, Removed the order of the old ones.
+type MatchDiff struct {
+	Added   []*Match
+	Removed []*Match
+	Changed []*MatchChange
+}
+
+func (d *MatchDiff) IsEmpty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+/** compares two lists of matches by match id, regardless of their order */
+func DiffMatchesById(old []*Match, new []*Match) *MatchDiff {
+	diff := &MatchDiff{}
+	before := make(map[int]*Match, len(old))
+	for _, m := range old {
+		before[m.Id] = m
+	}
+	seen := make(map[int]bool, len(new))
+	for _, m := range new {
+		seen[m.Id] = true
+		o, ok := before[m.Id]
+		if !ok {
+			diff.Added = append(diff.Added, m)
+			continue
+		}
+		if changes := compareMatches(o, m); len(changes) > 0 {
+			diff.Changed = append(diff.Changed, &MatchChange{Old: o, New: m, Changes: changes})
+		}
+	}
+	for _, m := range old {
+		if !seen[m.Id] {
+			diff.Removed = append(diff.Removed, m)
+		}
+	}
+	return diff
+}
+
+func compareMatches(old *Match, new *Match) []FieldChange {
+	changes := make([]FieldChange, 0)
+	add := func(field MatchField, o string, n string) {
+		if o != n {
+			changes = append(changes, FieldChange{Field: field, Old: o, New: n})
+		}
+	}
+	add(MatchFieldState, old.State, new.State)
+	add(MatchFieldScores, scoresOf(old), scoresOf(new))
+	add(MatchFieldWinner, strconv.Itoa(old.WinnerId), strconv.Itoa(new.WinnerId))
+	add(MatchFieldPlayerOne, strconv.Itoa(old.PlayerOneId), strconv.Itoa(new.PlayerOneId))
+	add(MatchFieldPlayerTwo, strconv.Itoa(old.PlayerTwoId), strconv.Itoa(new.PlayerTwoId))
+	add(MatchFieldUnderway, strconv.FormatBool(old.IsUnderway()), strconv.FormatBool(new.IsUnderway()))
+	return changes
+}
+
+func scoresOf(m *Match) string {
+	if len(m.Games) > 0 {
+		return FormatScores(m.Games)
+	}
+	return m.Scores
+}
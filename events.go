@@ -0,0 +1,250 @@
+package challonge
+
+import (
+	"sort"
+	"strconv"
+)
+
+// EventType identifies the kind of change an Event describes.
+type EventType string
+
+const (
+	EventTournamentSettingChanged EventType = "tournament.setting_changed"
+	EventTournamentStarted        EventType = "tournament.started"
+	EventTournamentReset          EventType = "tournament.reset"
+	EventTournamentAwaitingReview EventType = "tournament.awaiting_review"
+	EventTournamentCompleted      EventType = "tournament.completed"
+	EventTournamentStateChanged   EventType = "tournament.state_changed"
+
+	EventParticipantAdded       EventType = "participant.added"
+	EventParticipantRemoved     EventType = "participant.removed"
+	EventParticipantRenamed     EventType = "participant.renamed"
+	EventParticipantSeedChanged EventType = "participant.seed_changed"
+	EventParticipantCheckedIn   EventType = "participant.checked_in"
+	EventParticipantCheckedOut  EventType = "participant.checked_out"
+	EventParticipantDeactivated EventType = "participant.deactivated"
+
+	EventMatchAdded           EventType = "match.added"
+	EventMatchRemoved         EventType = "match.removed"
+	EventMatchOpened          EventType = "match.opened"
+	EventMatchPlayersChanged  EventType = "match.players_changed"
+	EventMatchUnderway        EventType = "match.underway"
+	EventMatchUnderwayCleared EventType = "match.underway_cleared"
+	EventMatchScoresChanged   EventType = "match.scores_changed"
+	EventMatchWinnerChanged   EventType = "match.winner_changed"
+	EventMatchCompleted       EventType = "match.completed"
+	EventMatchReopened        EventType = "match.reopened"
+)
+
+// Event is a single change to a tournament. Participant and Match are set
+// for participant and match events; Field, Old and New describe the value
+// that changed, when there is one. When both players of a match change at
+// once, Field is "players" and Old and New hold both ids, comma separated.
+type Event struct {
+	Type        EventType
+	Tournament  *Tournament
+	Participant *Participant
+	Match       *Match
+
+	Field string
+	Old   string
+	New   string
+}
+
+// DiffTournaments compares two snapshots of the same tournament and returns
+// what happened in between, in the order it would have happened: setting
+// edits, roster changes, the tournament starting, match progress and
+// finally the tournament finishing.
+func DiffTournaments(old *Tournament, new *Tournament) []Event {
+	events := make([]Event, 0)
+	emit := func(e Event) {
+		e.Tournament = new
+		events = append(events, e)
+	}
+
+	for _, e := range diffSettings(old, new) {
+		emit(e)
+	}
+	for _, e := range diffParticipants(old.Participants, new.Participants) {
+		emit(e)
+	}
+
+	finished := new.State == "awaiting_review" || new.State == "complete"
+	if old.State != new.State {
+		switch {
+		case new.State == "underway" && old.State == "pending":
+			emit(Event{Type: EventTournamentStarted, Field: "state", Old: old.State, New: new.State})
+		case new.State == "pending":
+			emit(Event{Type: EventTournamentReset, Field: "state", Old: old.State, New: new.State})
+		case !finished:
+			emit(Event{Type: EventTournamentStateChanged, Field: "state", Old: old.State, New: new.State})
+		}
+	}
+
+	for _, e := range matchEvents(DiffMatchesById(old.Matches, new.Matches)) {
+		emit(e)
+	}
+
+	if old.State != new.State && finished {
+		if old.State == "pending" {
+			emit(Event{Type: EventTournamentStarted, Field: "state", Old: old.State, New: "underway"})
+		}
+		if new.State == "awaiting_review" {
+			emit(Event{Type: EventTournamentAwaitingReview, Field: "state", Old: old.State, New: new.State})
+		} else {
+			emit(Event{Type: EventTournamentCompleted, Field: "state", Old: old.State, New: new.State})
+		}
+	}
+	return events
+}
+
+func diffSettings(old *Tournament, new *Tournament) []Event {
+	events := make([]Event, 0)
+	if old.Name != new.Name {
+		events = append(events, Event{Type: EventTournamentSettingChanged, Field: "name", Old: old.Name, New: new.Name})
+	}
+	before, after := old.settingsParams(), new.settingsParams()
+	keys := make([]string, 0, len(after))
+	for k := range after {
+		keys = append(keys, k)
+	}
+	for k := range before {
+		if _, ok := after[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		if before.Get(k) != after.Get(k) {
+			field := k[len("tournament[") : len(k)-1]
+			events = append(events, Event{Type: EventTournamentSettingChanged, Field: field, Old: before.Get(k), New: after.Get(k)})
+		}
+	}
+	return events
+}
+
+func diffParticipants(old []*Participant, new []*Participant) []Event {
+	events := make([]Event, 0)
+	before := make(map[int]*Participant, len(old))
+	for _, p := range old {
+		before[p.Id] = p
+	}
+	after := make(map[int]bool, len(new))
+	for _, p := range new {
+		after[p.Id] = true
+	}
+	for _, p := range old {
+		if !after[p.Id] {
+			events = append(events, Event{Type: EventParticipantRemoved, Participant: p})
+		}
+	}
+	for _, p := range new {
+		o, ok := before[p.Id]
+		if !ok {
+			events = append(events, Event{Type: EventParticipantAdded, Participant: p})
+			continue
+		}
+		if o.Name != p.Name {
+			events = append(events, Event{Type: EventParticipantRenamed, Participant: p, Field: "name", Old: o.Name, New: p.Name})
+		}
+		if o.Seed != p.Seed {
+			events = append(events, Event{Type: EventParticipantSeedChanged, Participant: p, Field: "seed", Old: strconv.Itoa(o.Seed), New: strconv.Itoa(p.Seed)})
+		}
+		if !o.CheckedIn && p.CheckedIn {
+			events = append(events, Event{Type: EventParticipantCheckedIn, Participant: p})
+		} else if o.CheckedIn && !p.CheckedIn {
+			events = append(events, Event{Type: EventParticipantCheckedOut, Participant: p})
+		}
+		if o.Active && !p.Active {
+			events = append(events, Event{Type: EventParticipantDeactivated, Participant: p})
+		}
+	}
+	return events
+}
+
+func matchEvents(diff *MatchDiff) []Event {
+	events := make([]Event, 0)
+	for _, m := range diff.Removed {
+		events = append(events, Event{Type: EventMatchRemoved, Match: m})
+	}
+	for _, m := range diff.Added {
+		events = append(events, Event{Type: EventMatchAdded, Match: m})
+	}
+	for _, c := range diff.Changed {
+		players := false
+		for _, f := range c.Changes {
+			e := Event{Match: c.New, Field: string(f.Field), Old: f.Old, New: f.New}
+			switch f.Field {
+			case MatchFieldState:
+				switch {
+				case f.New == "complete":
+					e.Type = EventMatchCompleted
+				case f.Old == "complete":
+					e.Type = EventMatchReopened
+				case f.New == "open":
+					e.Type = EventMatchOpened
+				default:
+					continue
+				}
+			case MatchFieldPlayerOne, MatchFieldPlayerTwo:
+				// both players are usually filled in at once, that's a single change
+				if players {
+					continue
+				}
+				players = true
+				e.Type = EventMatchPlayersChanged
+				if c.Has(MatchFieldPlayerOne) && c.Has(MatchFieldPlayerTwo) {
+					e.Field = "players"
+					e.Old = strconv.Itoa(c.Old.PlayerOneId) + "," + strconv.Itoa(c.Old.PlayerTwoId)
+					e.New = strconv.Itoa(c.New.PlayerOneId) + "," + strconv.Itoa(c.New.PlayerTwoId)
+				}
+			case MatchFieldUnderway:
+				if f.New == "true" {
+					e.Type = EventMatchUnderway
+				} else if c.New.State != "complete" {
+					e.Type = EventMatchUnderwayCleared
+				} else {
+					continue
+				}
+			case MatchFieldScores:
+				if c.Has(MatchFieldState) && c.New.State == "complete" {
+					// part of the completion
+					continue
+				}
+				e.Type = EventMatchScoresChanged
+			case MatchFieldWinner:
+				if c.Has(MatchFieldState) {
+					// part of the completion or reopening
+					continue
+				}
+				e.Type = EventMatchWinnerChanged
+			default:
+				continue
+			}
+			events = append(events, e)
+		}
+	}
+	// results come first, then what they cause downstream
+	sort.SliceStable(events, func(i, j int) bool {
+		return matchEventOrder(events[i].Type) < matchEventOrder(events[j].Type)
+	})
+	return events
+}
+
+func matchEventOrder(t EventType) int {
+	switch t {
+	case EventMatchRemoved, EventMatchAdded:
+		return 0
+	case EventMatchReopened:
+		return 1
+	case EventMatchUnderwayCleared:
+		return 2
+	case EventMatchCompleted, EventMatchScoresChanged, EventMatchWinnerChanged:
+		return 3
+	case EventMatchPlayersChanged:
+		return 4
+	case EventMatchOpened:
+		return 5
+	}
+	return 6
+}
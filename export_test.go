@@ -0,0 +1,49 @@
+package challonge
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+)
+
+// KeptInOrder exposes the participants ApplySeeding leaves in place.
+var KeptInOrder = keptInOrder
+
+// ReplaySeeding runs the seeding plan of ApplySeeding against the local
+// roster only, shifting seeds the way Challonge does, and returns the
+// number of participants moved.
+func (t *Tournament) ReplaySeeding(order []int) int {
+	moves := 0
+	t.planSeeding(order, func(id int, seed int) error {
+		p := t.GetParticipant(id)
+		t.shiftSeeds(id, p.Seed, seed)
+		p.Seed = seed
+		moves++
+		return nil
+	})
+	return moves
+}
+
+// UpdateSettings sets "tournament[...]" parameters of an existing
+// tournament, for recording fixtures.
+func (t *Tournament) UpdateSettings(settings map[string]string) error {
+	url := client.buildUrl("tournaments/"+t.GetUrl(), *params(settings))
+	return doRequest(context.Background(), "PUT", url, "", nil, nil)
+}
+
+// RawTournament returns the tournament response body, with participants
+// and matches, exactly as Challonge sent it.
+func (c *Client) RawTournament(id string) ([]byte, error) {
+	url := c.buildUrl("tournaments/"+id, url.Values{"include_participants": {"1"}, "include_matches": {"1"}})
+	resp, err := http.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unable to retrieve tournament: %s", resp.Status)
+	}
+	return ioutil.ReadAll(resp.Body)
+}
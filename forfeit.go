@@ -0,0 +1,107 @@
+package challonge
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+)
+
+// ForfeitResult reports the matches affected by Forfeit or Disqualify.
+type ForfeitResult struct {
+	// Forfeited holds the matches the participant lost by forfeit.
+	Forfeited []*Match
+	// Advanced holds the matches that received a player or opened as a
+	// consequence.
+	Advanced []*Match
+}
+
+// Forfeit awards m to the opponent of p without it being played. If the
+// matches can't be re-read afterwards, the forfeit still went through: the
+// result then only holds m and is returned along with the error.
+func (t *Tournament) Forfeit(m *Match, p *Participant) (*ForfeitResult, error) {
+	if m.State == "complete" {
+		return nil, fmt.Errorf("match %d is already complete", m.Id)
+	}
+	winner := m.PlayerOneId
+	if p.Id == m.PlayerOneId {
+		winner = m.PlayerTwoId
+	} else if p.Id != m.PlayerTwoId {
+		return nil, fmt.Errorf("participant %q is not playing match %d", p.Name, m.Id)
+	}
+	if winner == 0 {
+		return nil, fmt.Errorf("match %d has no opponent to advance yet", m.Id)
+	}
+
+	before := t.snapshotMatches()
+	forfeit := *m
+	forfeit.WinnerId = winner
+	if len(forfeit.Games) == 0 {
+		forfeit.Games = []GameScore{{0, 0}}
+	}
+	submitted, err := t.SubmitMatch(&forfeit)
+	if err != nil {
+		return nil, fmt.Errorf("unable to forfeit match: %v", err)
+	}
+	result, err := t.refreshAfterForfeit(before, p)
+	if err != nil {
+		result.Forfeited = append(result.Forfeited, t.mergeMatch(submitted))
+	}
+	return result, err
+}
+
+// Disqualify removes p from an underway tournament. Challonge keeps the
+// participant as inactive and forfeits every match they have left, which
+// advances their opponents. Use RemoveParticipantById before the start.
+// As with Forfeit, a non-nil result returned with an error means the
+// disqualification was applied but the matches couldn't be refreshed.
+func (t *Tournament) Disqualify(p *Participant) (*ForfeitResult, error) {
+	if t.State == "pending" {
+		return nil, fmt.Errorf("tournament %q has not started, remove the participant instead", t.Name)
+	}
+	before := t.snapshotMatches()
+	url := client.buildUrl("tournaments/"+t.GetUrl()+"/participants/"+strconv.Itoa(p.Id), nil)
+	response := &APIResponse{}
+	if err := doRequest(context.Background(), "DELETE", url, "", nil, response); err != nil {
+		return nil, fmt.Errorf("unable to disqualify participant: %v", err)
+	}
+	if response.Participant != nil {
+		*p = *response.Participant
+	} else {
+		p.Active = false
+	}
+	return t.refreshAfterForfeit(before, p)
+}
+
+/** copies the current matches by id */
+func (t *Tournament) snapshotMatches() map[int]Match {
+	snapshot := make(map[int]Match, len(t.Matches))
+	for _, m := range t.Matches {
+		snapshot[m.Id] = *m
+	}
+	return snapshot
+}
+
+// refreshAfterForfeit re-reads every match and sorts the changes into a
+// ForfeitResult. The forfeit is already applied when this runs, so a failed
+// refresh still returns an empty result along with the error.
+func (t *Tournament) refreshAfterForfeit(before map[int]Match, p *Participant) (*ForfeitResult, error) {
+	result := &ForfeitResult{}
+	matches, err := client.GetMatches(t.GetUrl(), MatchQuery{})
+	if err != nil {
+		return result, fmt.Errorf("forfeit applied, but unable to refresh matches: %w", err)
+	}
+	for _, updated := range matches {
+		m := t.mergeMatch(updated)
+		old, ok := before[m.Id]
+		if !ok {
+			continue
+		}
+		playing := m.PlayerOneId == p.Id || m.PlayerTwoId == p.Id
+		if playing && old.State != "complete" && m.State == "complete" && m.WinnerId != p.Id {
+			result.Forfeited = append(result.Forfeited, m)
+		} else if old.PlayerOneId != m.PlayerOneId || old.PlayerTwoId != m.PlayerTwoId || (old.State != "open" && m.State == "open") {
+			result.Advanced = append(result.Advanced, m)
+		}
+	}
+	return result, nil
+}
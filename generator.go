@@ -0,0 +1,260 @@
+package challonge
+
+import (
+	"fmt"
+	"sort"
+)
+
+/** where a player of a generated match comes from */
+type slot struct {
+	participant int    // set for a seeded participant
+	match       *Match // set for the winner or loser of a match
+	loser       bool
+}
+
+var bye = slot{}
+
+func (s slot) isBye() bool {
+	return s.participant == 0 && s.match == nil
+}
+
+/** a match of the complete bracket, before byes are removed */
+type pairing struct {
+	one, two slot
+	round    int
+	match    *Match
+}
+
+// GenerateBracket builds the matches of a single or double elimination
+// tournament from the current seeds, as a preview of starting it. Players
+// are placed with standard seeding (1 against the lowest seed, 1 and 2 in
+// opposite halves); top seeds get the byes when the field is not a power of
+// two. Losers drop into the losers bracket in reverse order in every round
+// after the first, keeping early rematches apart. Match ids are numbered
+// from 1 and identifiers follow the order the matches can be played in.
+// The layout follows Challonge's conventions but hasn't been compared with
+// matches created by Challonge itself, so identifiers may differ.
+func GenerateBracket(t *Tournament) ([]*Match, error) {
+	double := false
+	switch t.Type {
+	case "single elimination", "":
+	case "double elimination":
+		double = true
+	default:
+		return nil, fmt.Errorf("cannot generate a bracket for a %s tournament", t.Type)
+	}
+	participants := make([]*Participant, len(t.Participants))
+	copy(participants, t.Participants)
+	if len(participants) < 2 {
+		return nil, fmt.Errorf("a bracket needs at least 2 participants, got %d", len(participants))
+	}
+	sort.SliceStable(participants, func(i, j int) bool {
+		return participants[i].Seed < participants[j].Seed
+	})
+
+	size := 2
+	for size < len(participants) {
+		size *= 2
+	}
+	g := &generator{tournament: t}
+
+	// winners bracket
+	current := make([]slot, 0, size)
+	for _, seed := range bracketOrder(size) {
+		if seed <= len(participants) {
+			current = append(current, slot{participant: participants[seed-1].Id})
+		} else {
+			current = append(current, bye)
+		}
+	}
+	winners := make([][]slot, 0) // losers of each winners round
+	round := 1
+	var semiLosers []slot
+	for len(current) > 1 {
+		next := make([]slot, 0, len(current)/2)
+		losers := make([]slot, 0, len(current)/2)
+		for i := 0; i < len(current); i += 2 {
+			w, l := g.play(current[i], current[i+1], round)
+			next = append(next, w)
+			losers = append(losers, l)
+		}
+		if len(current) == 4 {
+			semiLosers = losers
+		}
+		winners = append(winners, losers)
+		current = next
+		round++
+	}
+	champion := current[0]
+	finalRound := round - 1
+
+	if !double {
+		if t.HoldThirdPlaceMatch && semiLosers != nil {
+			g.play(semiLosers[0], semiLosers[1], finalRound)
+		}
+		return g.finish(), nil
+	}
+
+	// losers bracket: losers of the first round meet each other, then every
+	// winners round drops its losers in, as player one, against the survivors
+	lround := -1
+	survivors := winners[0]
+	if len(survivors) > 1 {
+		survivors = g.halve(survivors, lround)
+		lround--
+	}
+	for r := 1; r < len(winners); r++ {
+		next := make([]slot, 0, len(survivors))
+		for i := range survivors {
+			w, _ := g.play(winners[r][len(winners[r])-1-i], survivors[i], lround)
+			next = append(next, w)
+		}
+		survivors = next
+		lround--
+		if len(survivors) > 1 {
+			survivors = g.halve(survivors, lround)
+			lround--
+		}
+	}
+
+	// grand finals, with a reset unless the tournament says otherwise
+	modifier := ""
+	if t.GrandFinalsModifier != nil {
+		modifier = *t.GrandFinalsModifier
+	}
+	if modifier != "skip" {
+		w, l := g.play(champion, survivors[0], finalRound+1)
+		if modifier != "single match" {
+			g.play(w, l, finalRound+1)
+		}
+	}
+	return g.finish(), nil
+}
+
+/** returns seeds in bracket position order, e.g. 1 8 4 5 2 7 3 6 for 8 slots */
+func bracketOrder(size int) []int {
+	order := []int{1}
+	for len(order) < size {
+		next := make([]int, 0, len(order)*2)
+		for _, seed := range order {
+			next = append(next, seed, len(order)*2+1-seed)
+		}
+		order = next
+	}
+	return order
+}
+
+type generator struct {
+	tournament *Tournament
+	pairings   []*pairing
+}
+
+// play adds a match between two slots and returns where its winner and
+// loser go. A match against a bye isn't played: the other slot goes
+// through and the loser is a bye.
+func (g *generator) play(one slot, two slot, round int) (slot, slot) {
+	if one.isBye() {
+		return two, bye
+	}
+	if two.isBye() {
+		return one, bye
+	}
+	m := &Match{Id: len(g.pairings) + 1, Round: round, TournamentId: g.tournament.Id}
+	g.pairings = append(g.pairings, &pairing{one: one, two: two, round: round, match: m})
+	return slot{match: m}, slot{match: m, loser: true}
+}
+
+/** pairs neighbouring slots and returns the winners */
+func (g *generator) halve(slots []slot, round int) []slot {
+	next := make([]slot, 0, len(slots)/2)
+	for i := 0; i < len(slots); i += 2 {
+		w, _ := g.play(slots[i], slots[i+1], round)
+		next = append(next, w)
+	}
+	return next
+}
+
+/** fills the generated matches and orders them for play */
+func (g *generator) finish() []*Match {
+	// losers rounds are played after the winners round feeding them
+	order := func(round int) int {
+		if round > 0 {
+			return round * 2
+		}
+		// losers rounds -1 and -2 follow winners round 2, -3 and -4 winners round 3...
+		return (-round+1)/2*2 + 3
+	}
+	sort.SliceStable(g.pairings, func(i, j int) bool {
+		return order(g.pairings[i].round) < order(g.pairings[j].round)
+	})
+
+	// pairings only refer to earlier ones, so ids can be renumbered in one pass
+	matches := make([]*Match, 0, len(g.pairings))
+	for i, p := range g.pairings {
+		m := p.match
+		m.Id = i + 1
+		m.Identifier = identifier(i)
+		order := i + 1
+		m.SuggestedPlayOrder = &order
+		g.fill(p.one, &m.PlayerOneId, &m.PlayerOnePrereqMatch, &m.PlayerOneIsPrereqMatchLoser)
+		g.fill(p.two, &m.PlayerTwoId, &m.PlayerTwoPrereqMatch, &m.PlayerTwoIsPrereqMatchLoser)
+		ids := ""
+		for _, prereq := range []*int{m.PlayerOnePrereqMatch, m.PlayerTwoPrereqMatch} {
+			if prereq != nil {
+				if ids != "" {
+					ids += ","
+				}
+				ids += fmt.Sprint(*prereq)
+			}
+		}
+		m.PrerequisiteMatchIds = ids
+		m.State = "pending"
+		if m.PlayerOneId != 0 && m.PlayerTwoId != 0 {
+			m.State = "open"
+		}
+		matches = append(matches, m)
+	}
+	compressLosersRounds(matches)
+	return matches
+}
+
+func (g *generator) fill(s slot, player *int, prereq **int, loser *bool) {
+	if s.match == nil {
+		*player = s.participant
+		return
+	}
+	id := s.match.Id
+	*prereq = &id
+	*loser = s.loser
+}
+
+/** numbers losers rounds -1, -2... without gaps left by bye rounds */
+func compressLosersRounds(matches []*Match) {
+	rounds := make([]int, 0)
+	seen := make(map[int]bool)
+	for _, m := range matches {
+		if m.Round < 0 && !seen[m.Round] {
+			seen[m.Round] = true
+			rounds = append(rounds, m.Round)
+		}
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(rounds)))
+	renumber := make(map[int]int, len(rounds))
+	for i, r := range rounds {
+		renumber[r] = -(i + 1)
+	}
+	for _, m := range matches {
+		if m.Round < 0 {
+			m.Round = renumber[m.Round]
+		}
+	}
+}
+
+/** returns A, B, ... Z, AA, AB ... */
+func identifier(i int) string {
+	s := ""
+	for i++; i > 0; i = (i - 1) / 26 {
+		s = string(rune('A'+(i-1)%26)) + s
+	}
+	return s
+}
@@ -0,0 +1,175 @@
+package challonge
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// MatchQuery filters the match index; zero values are left out.
+type MatchQuery struct {
+	State         string // all, pending, open, complete
+	ParticipantId int
+}
+
+func (q MatchQuery) values() url.Values {
+	v := url.Values{}
+	if q.State != "" {
+		v.Set("state", q.State)
+	}
+	if q.ParticipantId != 0 {
+		v.Set("participant_id", strconv.Itoa(q.ParticipantId))
+	}
+	return v
+}
+
+/** lists the matches of a tournament without fetching the tournament itself */
+func (c *Client) GetMatches(tournament string, q MatchQuery) ([]*Match, error) {
+	url := c.buildUrl("tournaments/"+tournament+"/matches", q.values())
+	response := []MatchItem{}
+	if err := doRequest(context.Background(), "GET", url, "", nil, &response); err != nil {
+		return nil, fmt.Errorf("unable to list matches: %v", err)
+	}
+	matches := make([]*Match, 0, len(response))
+	for _, item := range response {
+		if item.Match != nil {
+			matches = append(matches, item.Match)
+		}
+	}
+	return matches, nil
+}
+
+/** shows a single match */
+func (c *Client) ShowMatch(tournament string, id int) (*Match, error) {
+	url := c.buildUrl("tournaments/"+tournament+"/matches/"+strconv.Itoa(id), nil)
+	response := &APIResponse{}
+	if err := doRequest(context.Background(), "GET", url, "", nil, response); err != nil {
+		return nil, fmt.Errorf("unable to retrieve match: %v", err)
+	}
+	if response.hasErrors() {
+		return nil, fmt.Errorf("unable to retrieve match: %q", response.Errors[0])
+	}
+	if response.Match.Id == 0 {
+		return nil, fmt.Errorf("unable to retrieve match: no match in response")
+	}
+	return &response.Match, nil
+}
+
+/** marks a match as currently being played */
+func (m *Match) MarkAsUnderway() error {
+	return m.setUnderway("mark_as_underway")
+}
+
+/** clears the underway flag of a match */
+func (m *Match) UnmarkAsUnderway() error {
+	return m.setUnderway("unmark_as_underway")
+}
+
+func (m *Match) IsUnderway() bool {
+	return m.UnderwayAt != nil
+}
+
+func (m *Match) setUnderway(action string) error {
+	url := client.buildUrl(fmt.Sprintf("tournaments/%d/matches/%d/%s", m.TournamentId, m.Id, action), nil)
+	response := &APIResponse{}
+	if err := doRequest(context.Background(), "POST", url, "", nil, response); err != nil {
+		return fmt.Errorf("unable to %s match: %v", strings.ReplaceAll(action, "_", " "), err)
+	}
+	m.UnderwayAt = response.Match.UnderwayAt
+	m.UpdatedAt = response.Match.UpdatedAt
+	return nil
+}
+
+/** returns the open matches that are marked as underway */
+func (t *Tournament) GetUnderwayMatches() []*Match {
+	matches := make([]*Match, 0)
+	for _, m := range t.GetOpenMatches() {
+		if m.IsUnderway() {
+			matches = append(matches, m)
+		}
+	}
+	return matches
+}
+
+// ReopenResult is returned by ReopenMatch.
+type ReopenResult struct {
+	Match *Match
+	// Reset holds the downstream matches whose result or players were
+	// cleared because of the reopen.
+	Reset []*Match
+}
+
+/** reopens a completed match and refreshes the matches depending on it */
+func (t *Tournament) ReopenMatch(m *Match) (*ReopenResult, error) {
+	downstream := t.dependentMatches(m.Id)
+	before := make(map[int]Match, len(downstream))
+	for _, d := range downstream {
+		before[d.Id] = *d
+	}
+
+	url := client.buildUrl(fmt.Sprintf("tournaments/%s/matches/%d/reopen", t.GetUrl(), m.Id), nil)
+	response := &APIResponse{}
+	if err := doRequest(context.Background(), "POST", url, "", nil, response); err != nil {
+		return nil, fmt.Errorf("unable to reopen match: %v", err)
+	}
+	result := &ReopenResult{Match: t.mergeMatch(&response.Match)}
+
+	matches, err := client.GetMatches(t.GetUrl(), MatchQuery{})
+	if err != nil {
+		return result, err
+	}
+	for _, updated := range matches {
+		if updated.Id == m.Id {
+			continue
+		}
+		merged := t.mergeMatch(updated)
+		old, ok := before[updated.Id]
+		if !ok {
+			continue
+		}
+		if old.State != merged.State || old.WinnerId != merged.WinnerId ||
+			old.PlayerOneId != merged.PlayerOneId || old.PlayerTwoId != merged.PlayerTwoId {
+			result.Reset = append(result.Reset, merged)
+		}
+	}
+	return result, nil
+}
+
+/** returns every match that is fed, directly or not, by the match with the given id */
+func (t *Tournament) dependentMatches(id int) []*Match {
+	dependents := make([]*Match, 0)
+	seen := map[int]bool{id: true}
+	queue := []int{id}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, m := range t.Matches {
+			if seen[m.Id] {
+				continue
+			}
+			if (m.PlayerOnePrereqMatch != nil && *m.PlayerOnePrereqMatch == current) ||
+				(m.PlayerTwoPrereqMatch != nil && *m.PlayerTwoPrereqMatch == current) {
+				seen[m.Id] = true
+				dependents = append(dependents, m)
+				queue = append(queue, m.Id)
+			}
+		}
+	}
+	return dependents
+}
+
+/** copies updated into the local match with the same id, keeping pointers held by callers valid */
+func (t *Tournament) mergeMatch(updated *Match) *Match {
+	for _, m := range t.Matches {
+		if m.Id == updated.Id {
+			*m = *updated
+			m.ResolveParticipants(t)
+			return m
+		}
+	}
+	t.Matches = append(t.Matches, updated)
+	updated.ResolveParticipants(t)
+	return updated
+}
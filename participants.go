@@ -0,0 +1,350 @@
+package challonge
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+)
+
+/** maximum number of participants sent in a single bulk_add call */
+const bulkAddChunkSize = 50
+
+// ParticipantInput describes a participant to be created.
+type ParticipantInput struct {
+	Name              string
+	Seed              int
+	Misc              string
+	Email             string
+	ChallongeUsername string
+}
+
+/** wire format of a bulk_add entry, email and username share one field */
+type bulkAddEntry struct {
+	Name              string `json:"name,omitempty"`
+	InviteNameOrEmail string `json:"invite_name_or_email,omitempty"`
+	Seed              int    `json:"seed,omitempty"`
+	Misc              string `json:"misc,omitempty"`
+}
+
+/** expects at most one of Email and ChallongeUsername to be set */
+func (p ParticipantInput) bulkAddEntry() bulkAddEntry {
+	invite := p.Email
+	if invite == "" {
+		invite = p.ChallongeUsername
+	}
+	return bulkAddEntry{Name: p.Name, InviteNameOrEmail: invite, Seed: p.Seed, Misc: p.Misc}
+}
+
+// BulkAddError is the failure of a single entry passed to BulkAddParticipants.
+type BulkAddError struct {
+	Index int
+	Entry ParticipantInput
+	Err   error
+}
+
+func (e *BulkAddError) Error() string {
+	return fmt.Sprintf("entry %d (%q): %v", e.Index, e.Entry.Name, e.Err)
+}
+
+// BulkAddResult holds the participants created by BulkAddParticipants and
+// the entries that could not be added.
+type BulkAddResult struct {
+	Participants []*Participant
+	Errors       []*BulkAddError
+}
+
+/** adds many participants at once using the bulk_add endpoint */
+func (t *Tournament) BulkAddParticipants(entries []ParticipantInput) *BulkAddResult {
+	result := &BulkAddResult{}
+	url := client.buildUrl("tournaments/"+t.GetUrl()+"/participants/bulk_add", nil)
+
+	chunk := make([]ParticipantInput, 0, bulkAddChunkSize)
+	indexes := make([]int, 0, bulkAddChunkSize)
+	flush := func() {
+		if len(chunk) == 0 {
+			return
+		}
+		wire := make([]bulkAddEntry, 0, len(chunk))
+		for _, entry := range chunk {
+			wire = append(wire, entry.bulkAddEntry())
+		}
+		body, err := json.Marshal(map[string][]bulkAddEntry{"participants": wire})
+		response := []ParticipantItem{}
+		if err == nil {
+			err = doRequest(context.Background(), "POST", url, "application/json", bytes.NewReader(body), &response)
+		}
+		if err != nil {
+			// the endpoint rejects a chunk as a whole
+			for i, entry := range chunk {
+				result.Errors = append(result.Errors, &BulkAddError{Index: indexes[i], Entry: entry, Err: err})
+			}
+		} else {
+			for i := range response {
+				p := response[i].Participant
+				result.Participants = append(result.Participants, &p)
+				t.Participants = append(t.Participants, &p)
+			}
+		}
+		chunk = chunk[:0]
+		indexes = indexes[:0]
+	}
+
+	for i, entry := range entries {
+		if entry.Name == "" && entry.Email == "" && entry.ChallongeUsername == "" {
+			result.Errors = append(result.Errors, &BulkAddError{Index: i, Entry: entry, Err: fmt.Errorf("participant needs a name, email or challonge username")})
+			continue
+		}
+		if entry.Email != "" && entry.ChallongeUsername != "" {
+			// bulk_add takes either one in a single field
+			result.Errors = append(result.Errors, &BulkAddError{Index: i, Entry: entry, Err: fmt.Errorf("participant can't have both an email and a challonge username")})
+			continue
+		}
+		chunk = append(chunk, entry)
+		indexes = append(indexes, i)
+		if len(chunk) == bulkAddChunkSize {
+			flush()
+		}
+	}
+	flush()
+	return result
+}
+
+// ParticipantUpdate lists the participant fields to change; nil fields are
+// left untouched.
+type ParticipantUpdate struct {
+	Name              *string
+	Seed              *int
+	Misc              *string
+	Email             *string
+	ChallongeUsername *string
+
+	// InviteNameOrEmail invites a Challonge user by username or email.
+	InviteNameOrEmail *string
+}
+
+func (u ParticipantUpdate) values() url.Values {
+	v := url.Values{}
+	if u.Name != nil {
+		v.Set("participant[name]", *u.Name)
+	}
+	if u.Seed != nil {
+		v.Set("participant[seed]", strconv.Itoa(*u.Seed))
+	}
+	if u.Misc != nil {
+		v.Set("participant[misc]", *u.Misc)
+	}
+	if u.Email != nil {
+		v.Set("participant[email]", *u.Email)
+	}
+	if u.ChallongeUsername != nil {
+		v.Set("participant[challonge_username]", *u.ChallongeUsername)
+	}
+	if u.InviteNameOrEmail != nil {
+		v.Set("participant[invite_name_or_email]", *u.InviteNameOrEmail)
+	}
+	return v
+}
+
+/** updates a participant and keeps the local roster in sync */
+func (t *Tournament) UpdateParticipant(id int, u ParticipantUpdate) (*Participant, error) {
+	url := client.buildUrl("tournaments/"+t.GetUrl()+"/participants/"+strconv.Itoa(id), u.values())
+	response := &APIResponse{}
+	if err := doRequest(context.Background(), "PUT", url, "", nil, response); err != nil {
+		return nil, fmt.Errorf("unable to update participant: %v", err)
+	}
+	if response.hasErrors() {
+		return nil, fmt.Errorf("unable to update participant: %q", response.Errors[0])
+	}
+	updated := response.Participant
+	if updated == nil {
+		return nil, fmt.Errorf("unable to update participant: no participant in response")
+	}
+
+	p := t.GetParticipant(id)
+	if p == nil {
+		t.Participants = append(t.Participants, updated)
+		return updated, nil
+	}
+	if p.Seed != updated.Seed {
+		t.shiftSeeds(id, p.Seed, updated.Seed)
+	}
+	*p = *updated
+	return p, nil
+}
+
+/** mirrors the seed shift Challonge applies when a participant moves from seed "from" to seed "to" */
+func (t *Tournament) shiftSeeds(id int, from int, to int) {
+	for _, p := range t.Participants {
+		if p.Id == id {
+			continue
+		}
+		if to < from && p.Seed >= to && p.Seed < from {
+			p.Seed++
+		} else if to > from && p.Seed > from && p.Seed <= to {
+			p.Seed--
+		}
+	}
+}
+
+/** lists the participants of a tournament without fetching the tournament itself */
+func (c *Client) GetParticipants(tournament string) ([]*Participant, error) {
+	url := c.buildUrl("tournaments/"+tournament+"/participants", nil)
+	response := []ParticipantItem{}
+	if err := doRequest(context.Background(), "GET", url, "", nil, &response); err != nil {
+		return nil, fmt.Errorf("unable to list participants: %v", err)
+	}
+	participants := make([]*Participant, 0, len(response))
+	for i := range response {
+		participants = append(participants, &response[i].Participant)
+	}
+	return participants, nil
+}
+
+/** shows a single participant */
+func (c *Client) ShowParticipant(tournament string, id int) (*Participant, error) {
+	url := c.buildUrl("tournaments/"+tournament+"/participants/"+strconv.Itoa(id), nil)
+	response := &APIResponse{}
+	if err := doRequest(context.Background(), "GET", url, "", nil, response); err != nil {
+		return nil, fmt.Errorf("unable to retrieve participant: %v", err)
+	}
+	if response.hasErrors() {
+		return nil, fmt.Errorf("unable to retrieve participant: %q", response.Errors[0])
+	}
+	if response.Participant == nil {
+		return nil, fmt.Errorf("unable to retrieve participant: no participant in response")
+	}
+	return response.Participant, nil
+}
+
+/** returns an error unless the participant list of t can still be changed */
+func (t *Tournament) checkPending() error {
+	if t.State != "" && t.State != "pending" {
+		return fmt.Errorf("tournament %q is %s, roster can only change while pending", t.Name, t.State)
+	}
+	return nil
+}
+
+/** removes every participant from a pending tournament */
+func (t *Tournament) ClearParticipants() error {
+	if err := t.checkPending(); err != nil {
+		return err
+	}
+	url := client.buildUrl("tournaments/"+t.GetUrl()+"/participants/clear", nil)
+	if err := doRequest(context.Background(), "DELETE", url, "", nil, nil); err != nil {
+		return fmt.Errorf("unable to clear participants: %v", err)
+	}
+	t.Participants = nil
+	return nil
+}
+
+// ApplySeeding reorders the participants of a pending tournament so that
+// order[0] becomes seed 1, order[1] seed 2 and so on. Participants that
+// are already in the right relative order are left alone, so only the
+// others are sent to the API. The final order is read back and verified.
+func (t *Tournament) ApplySeeding(order []int) error {
+	if err := t.checkPending(); err != nil {
+		return err
+	}
+	participants, err := client.GetParticipants(t.GetUrl())
+	if err != nil {
+		return err
+	}
+	if len(order) != len(participants) {
+		return fmt.Errorf("seeding lists %d participants, tournament has %d", len(order), len(participants))
+	}
+	t.Participants = participants
+	seen := make(map[int]bool, len(order))
+	for _, id := range order {
+		if t.GetParticipant(id) == nil {
+			return fmt.Errorf("participant %d not found in tournament", id)
+		}
+		if seen[id] {
+			return fmt.Errorf("participant %d listed twice", id)
+		}
+		seen[id] = true
+	}
+
+	err = t.planSeeding(order, func(id int, seed int) error {
+		_, err := t.UpdateParticipant(id, ParticipantUpdate{Seed: &seed})
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	participants, err = client.GetParticipants(t.GetUrl())
+	if err != nil {
+		return err
+	}
+	t.Participants = participants
+	for i, id := range order {
+		if p := t.GetParticipant(id); p == nil || p.Seed != i+1 {
+			return fmt.Errorf("seeding mismatch: participant %d should be seed %d", id, i+1)
+		}
+	}
+	return nil
+}
+
+// planSeeding calls move for every participant that has to change seed to
+// follow order, in turn. move must update the local seeds, as
+// UpdateParticipant does, since each target depends on the previous moves.
+func (t *Tournament) planSeeding(order []int, move func(id int, seed int) error) error {
+	keep := keptInOrder(order, func(id int) int { return t.GetParticipant(id).Seed })
+	for i, id := range order {
+		if keep[id] {
+			continue
+		}
+		seed := 1
+		if i > 0 {
+			// place the participant right behind its predecessor
+			current := t.GetParticipant(id).Seed
+			seed = t.GetParticipant(order[i-1]).Seed
+			if current > seed {
+				seed++
+			}
+		}
+		if err := move(id, seed); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+/** returns the longest run of ids whose current seeds already increase, these don't need to move */
+func keptInOrder(order []int, seed func(int) int) map[int]bool {
+	// patience sorting, tails[k] is the index in order ending the best run of length k+1
+	tails := make([]int, 0, len(order))
+	prev := make([]int, len(order))
+	for i, id := range order {
+		s := seed(id)
+		lo, hi := 0, len(tails)
+		for lo < hi {
+			mid := (lo + hi) / 2
+			if seed(order[tails[mid]]) < s {
+				lo = mid + 1
+			} else {
+				hi = mid
+			}
+		}
+		prev[i] = -1
+		if lo > 0 {
+			prev[i] = tails[lo-1]
+		}
+		if lo == len(tails) {
+			tails = append(tails, i)
+		} else {
+			tails[lo] = i
+		}
+	}
+	keep := make(map[int]bool, len(tails))
+	if len(tails) == 0 {
+		return keep
+	}
+	for i := tails[len(tails)-1]; i >= 0; i = prev[i] {
+		keep[order[i]] = true
+	}
+	return keep
+}
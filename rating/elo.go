@@ -0,0 +1,17 @@
+package rating
+
+import "math"
+
+/** expected score of a rating against another */
+func expected(rating float64, opponent float64) float64 {
+	return 1 / (1 + math.Pow(10, (opponent-rating)/400))
+}
+
+/** updates both players after every match, in order */
+func (e *Engine) elo(results []result) {
+	for _, r := range results {
+		change := e.opts.K * (r.score - expected(r.one.Rating, r.two.Rating))
+		r.one.Rating += change
+		r.two.Rating -= change
+	}
+}
@@ -0,0 +1,89 @@
+package rating
+
+import "math"
+
+const (
+	glickoScale     = 173.7178
+	glickoTolerance = 0.000001
+)
+
+/** a game of a rating period, against the opponent's rating at the start of the period */
+type glickoGame struct {
+	mu, phi float64
+	score   float64
+}
+
+// glicko2 treats the tournament as one rating period: every player is
+// updated from all of their matches at once, against the ratings their
+// opponents had before the tournament. Rated players who didn't play see
+// their deviation grow. See http://www.glicko.net/glicko/glicko2.pdf
+func (e *Engine) glicko2(results []result) {
+	games := make(map[*Player][]glickoGame)
+	for _, r := range results {
+		games[r.one] = append(games[r.one], glickoGame{mu: toMu(r.two.Rating), phi: r.two.Deviation / glickoScale, score: r.score})
+		games[r.two] = append(games[r.two], glickoGame{mu: toMu(r.one.Rating), phi: r.one.Deviation / glickoScale, score: 1 - r.score})
+	}
+	for _, p := range e.players {
+		phi := p.Deviation / glickoScale
+		played := games[p]
+		if len(played) == 0 {
+			phi = math.Sqrt(phi*phi + p.Volatility*p.Volatility)
+			p.Deviation = math.Min(phi*glickoScale, e.opts.InitialDeviation)
+			continue
+		}
+		mu := toMu(p.Rating)
+		v, sum := 0.0, 0.0
+		for _, g := range played {
+			gphi := 1 / math.Sqrt(1+3*g.phi*g.phi/(math.Pi*math.Pi))
+			expected := 1 / (1 + math.Exp(-gphi*(mu-g.mu)))
+			v += gphi * gphi * expected * (1 - expected)
+			sum += gphi * (g.score - expected)
+		}
+		v = 1 / v
+		sigma := e.volatility(phi, p.Volatility, v, v*sum)
+		phiStar := math.Sqrt(phi*phi + sigma*sigma)
+		phi = 1 / math.Sqrt(1/(phiStar*phiStar)+1/v)
+		mu += phi * phi * sum
+
+		p.Rating = mu*glickoScale + defaultRating
+		p.Deviation = phi * glickoScale
+		p.Volatility = sigma
+	}
+}
+
+func toMu(rating float64) float64 {
+	return (rating - defaultRating) / glickoScale
+}
+
+/** finds the new volatility with the Illinois algorithm, step 5 of the paper */
+func (e *Engine) volatility(phi float64, sigma float64, v float64, delta float64) float64 {
+	tau := e.opts.Tau
+	a := math.Log(sigma * sigma)
+	f := func(x float64) float64 {
+		ex := math.Exp(x)
+		d := phi*phi + v + ex
+		return ex*(delta*delta-d)/(2*d*d) - (x-a)/(tau*tau)
+	}
+	lo, hi := a, 0.0
+	if delta*delta > phi*phi+v {
+		hi = math.Log(delta*delta - phi*phi - v)
+	} else {
+		k := 1.0
+		for f(a-k*tau) < 0 {
+			k++
+		}
+		hi = a - k*tau
+	}
+	flo, fhi := f(lo), f(hi)
+	for math.Abs(hi-lo) > glickoTolerance {
+		c := lo + (lo-hi)*flo/(fhi-flo)
+		fc := f(c)
+		if fc*fhi <= 0 {
+			lo, flo = hi, fhi
+		} else {
+			flo /= 2
+		}
+		hi, fhi = c, fc
+	}
+	return math.Exp(lo / 2)
+}
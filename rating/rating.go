@@ -0,0 +1,344 @@
+// Package rating rates players over a series of completed tournaments with
+// Elo or Glicko-2. Players are linked across tournaments by the misc field
+// or their challonge username, so the same person entering several events
+// keeps a single rating and history.
+package rating
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/FlowingSPDG/go-challonge"
+)
+
+type System string
+
+const (
+	Elo     System = "elo"
+	Glicko2 System = "glicko2"
+)
+
+const (
+	defaultRating     = 1500
+	defaultK          = 32
+	defaultDeviation  = 350
+	defaultVolatility = 0.06
+	defaultTau        = 0.5
+)
+
+// Options configures an Engine. Zero values take the usual defaults: Elo
+// with K 32, players starting at 1500, and for Glicko-2 a deviation of 350,
+// a volatility of 0.06 and a tau of 0.5.
+type Options struct {
+	System  System
+	Initial float64
+	// K is the Elo K-factor.
+	K                 float64
+	InitialDeviation  float64
+	InitialVolatility float64
+	Tau               float64
+	// Key links a participant to a player across tournaments, nil uses
+	// PlayerKey. Participants with an empty key are not rated.
+	Key func(*challonge.Participant) string
+}
+
+// PlayerKey identifies a participant by its misc field, then its challonge
+// username. Participants with neither aren't linked, and so aren't rated:
+// display names aren't unique enough to tell players apart across events.
+func PlayerKey(p *challonge.Participant) string {
+	if misc := strings.TrimSpace(p.Misc); misc != "" {
+		return "misc:" + misc
+	}
+	if p.ChallongeUsername != nil && *p.ChallongeUsername != "" {
+		return "user:" + strings.ToLower(*p.ChallongeUsername)
+	}
+	if p.Username != "" {
+		return "user:" + strings.ToLower(p.Username)
+	}
+	return ""
+}
+
+// Entry is the rating of a player after a tournament.
+type Entry struct {
+	TournamentId int        `json:"tournament_id"`
+	Tournament   string     `json:"tournament"`
+	At           *time.Time `json:"at,omitempty"`
+	Rating       float64    `json:"rating"`
+	Deviation    float64    `json:"deviation,omitempty"`
+	Volatility   float64    `json:"volatility,omitempty"`
+	Change       float64    `json:"change"`
+	Wins         int        `json:"wins"`
+	Losses       int        `json:"losses"`
+	Ties         int        `json:"ties"`
+}
+
+type Player struct {
+	Key string `json:"key"`
+	// Name is the display name the player last entered with.
+	Name       string  `json:"name"`
+	Rating     float64 `json:"rating"`
+	Deviation  float64 `json:"deviation,omitempty"`
+	Volatility float64 `json:"volatility,omitempty"`
+	Matches    int     `json:"matches"`
+	History    []Entry `json:"history"`
+}
+
+// Engine holds the ratings of every player seen so far. Tournaments are
+// added as they finish, each one updating the ratings left by the previous.
+type Engine struct {
+	opts        Options
+	players     map[string]*Player
+	tournaments map[int]bool
+}
+
+func NewEngine(opts *Options) *Engine {
+	o := Options{}
+	if opts != nil {
+		o = *opts
+	}
+	if o.System == "" {
+		o.System = Elo
+	}
+	if o.Initial == 0 {
+		o.Initial = defaultRating
+	}
+	if o.K == 0 {
+		o.K = defaultK
+	}
+	if o.InitialDeviation == 0 {
+		o.InitialDeviation = defaultDeviation
+	}
+	if o.InitialVolatility == 0 {
+		o.InitialVolatility = defaultVolatility
+	}
+	if o.Tau == 0 {
+		o.Tau = defaultTau
+	}
+	if o.Key == nil {
+		o.Key = PlayerKey
+	}
+	return &Engine{
+		opts:        o,
+		players:     make(map[string]*Player),
+		tournaments: make(map[int]bool),
+	}
+}
+
+/** a rated match, scores being 1 for a win of one, 0.5 for a tie and 0 for a loss */
+type result struct {
+	one, two *Player
+	score    float64
+}
+
+// Add rates the matches of a completed tournament. Matches are processed in
+// the order they were completed; forfeits and matches against unknown
+// players are skipped. A tournament can only be added once.
+func (e *Engine) Add(t *challonge.Tournament) error {
+	if t.State != "complete" {
+		return fmt.Errorf("tournament %s is %s, not complete", t.Url, t.State)
+	}
+	if t.Id != 0 && e.tournaments[t.Id] {
+		return fmt.Errorf("tournament %s was already rated", t.Url)
+	}
+	if e.opts.System != Elo && e.opts.System != Glicko2 {
+		return fmt.Errorf("unknown rating system %q", e.opts.System)
+	}
+
+	// matches of a group stage refer to participants by their group player ids
+	byId := make(map[int]*Player)
+	for _, p := range t.Participants {
+		key := e.opts.Key(p)
+		if key == "" {
+			continue
+		}
+		player := e.players[key]
+		if player == nil {
+			player = &Player{Key: key, Rating: e.opts.Initial}
+			if e.opts.System == Glicko2 {
+				player.Deviation = e.opts.InitialDeviation
+				player.Volatility = e.opts.InitialVolatility
+			}
+			e.players[key] = player
+		}
+		player.Name = p.Name
+		byId[p.Id] = player
+		for _, id := range p.GroupPlayerIds {
+			byId[id] = player
+		}
+	}
+
+	results := make([]result, 0, len(t.Matches))
+	for _, m := range matchOrder(t.Matches) {
+		if m.State != "complete" || (m.Forfeited != nil && *m.Forfeited) {
+			continue
+		}
+		one, two := byId[m.PlayerOneId], byId[m.PlayerTwoId]
+		if one == nil || two == nil || one == two {
+			continue
+		}
+		r := result{one: one, two: two, score: 0.5}
+		switch m.WinnerId {
+		case m.PlayerOneId:
+			r.score = 1
+		case m.PlayerTwoId:
+			r.score = 0
+		}
+		results = append(results, r)
+	}
+
+	before := make(map[*Player]float64, len(byId))
+	for _, p := range byId {
+		before[p] = p.Rating
+	}
+	if e.opts.System == Glicko2 {
+		e.glicko2(results)
+	} else {
+		e.elo(results)
+	}
+
+	entries := make(map[*Player]*Entry)
+	for _, r := range results {
+		for _, p := range []*Player{r.one, r.two} {
+			if entries[p] == nil {
+				entries[p] = &Entry{TournamentId: t.Id, Tournament: t.Name, At: t.StartedAt}
+			}
+			p.Matches++
+		}
+		switch r.score {
+		case 1:
+			entries[r.one].Wins++
+			entries[r.two].Losses++
+		case 0:
+			entries[r.one].Losses++
+			entries[r.two].Wins++
+		default:
+			entries[r.one].Ties++
+			entries[r.two].Ties++
+		}
+	}
+	for p, entry := range entries {
+		entry.Rating = p.Rating
+		entry.Deviation = p.Deviation
+		entry.Volatility = p.Volatility
+		entry.Change = p.Rating - before[p]
+		p.History = append(p.History, *entry)
+	}
+	if t.Id != 0 {
+		e.tournaments[t.Id] = true
+	}
+	return nil
+}
+
+// AddAll adds tournaments in the order they started, skipping any already
+// rated. It stops at the first tournament that can't be rated.
+func (e *Engine) AddAll(tournaments []*challonge.Tournament) error {
+	sorted := append([]*challonge.Tournament{}, tournaments...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		a, b := sorted[i].StartedAt, sorted[j].StartedAt
+		if a != nil && b != nil && !a.Equal(*b) {
+			return a.Before(*b)
+		}
+		return sorted[i].Id < sorted[j].Id
+	})
+	for _, t := range sorted {
+		if e.Rated(t.Id) {
+			continue
+		}
+		if err := e.Add(t); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+/** returns true when the tournament with the given id was added */
+func (e *Engine) Rated(id int) bool {
+	return e.tournaments[id]
+}
+
+/** returns the player with the given key, or nil */
+func (e *Engine) Player(key string) *Player {
+	return e.players[key]
+}
+
+/** returns the player a participant is linked to, or nil */
+func (e *Engine) PlayerFor(p *challonge.Participant) *Player {
+	return e.players[e.opts.Key(p)]
+}
+
+// Ranking returns every player, highest rated first. With Glicko-2, players
+// of equal rating are ordered by the lower deviation.
+func (e *Engine) Ranking() []*Player {
+	players := make([]*Player, 0, len(e.players))
+	for _, p := range e.players {
+		players = append(players, p)
+	}
+	sort.Slice(players, func(i, j int) bool {
+		if players[i].Rating != players[j].Rating {
+			return players[i].Rating > players[j].Rating
+		}
+		if players[i].Deviation != players[j].Deviation {
+			return players[i].Deviation < players[j].Deviation
+		}
+		return players[i].Key < players[j].Key
+	})
+	return players
+}
+
+/** the saved state of an engine */
+type state struct {
+	System      System    `json:"system"`
+	Tournaments []int     `json:"tournaments"`
+	Players     []*Player `json:"players"`
+}
+
+// Save writes the ratings as JSON so that later tournaments can be added
+// after Load, without processing every tournament again.
+func (e *Engine) Save(w io.Writer) error {
+	s := state{System: e.opts.System, Tournaments: make([]int, 0, len(e.tournaments)), Players: e.Ranking()}
+	for id := range e.tournaments {
+		s.Tournaments = append(s.Tournaments, id)
+	}
+	sort.Ints(s.Tournaments)
+	return json.NewEncoder(w).Encode(s)
+}
+
+// Load reads ratings written by Save. The saved system must match the one
+// of opts.
+func Load(r io.Reader, opts *Options) (*Engine, error) {
+	e := NewEngine(opts)
+	s := state{}
+	if err := json.NewDecoder(r).Decode(&s); err != nil {
+		return nil, err
+	}
+	if s.System != e.opts.System {
+		return nil, fmt.Errorf("saved ratings use %s, not %s", s.System, e.opts.System)
+	}
+	for _, id := range s.Tournaments {
+		e.tournaments[id] = true
+	}
+	for _, p := range s.Players {
+		e.players[p.Key] = p
+	}
+	return e, nil
+}
+
+/** orders matches by completion time, then suggested play order and id */
+func matchOrder(matches []*challonge.Match) []*challonge.Match {
+	sorted := append([]*challonge.Match{}, matches...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		a, b := sorted[i], sorted[j]
+		if a.CompletedAt != nil && b.CompletedAt != nil && !a.CompletedAt.Equal(*b.CompletedAt) {
+			return a.CompletedAt.Before(*b.CompletedAt)
+		}
+		if a.SuggestedPlayOrder != nil && b.SuggestedPlayOrder != nil && *a.SuggestedPlayOrder != *b.SuggestedPlayOrder {
+			return *a.SuggestedPlayOrder < *b.SuggestedPlayOrder
+		}
+		return a.Id < b.Id
+	})
+	return sorted
+}
@@ -0,0 +1,117 @@
+package rating_test
+
+import (
+	"bytes"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/FlowingSPDG/go-challonge"
+	"github.com/FlowingSPDG/go-challonge/rating"
+)
+
+// finished returns a completed tournament where the first player of every
+// pair beats the second.
+func finished(id int, start time.Time, participants []*challonge.Participant, wins [][2]int) *challonge.Tournament {
+	t := &challonge.Tournament{Id: id, Name: "weekly", Url: "weekly", State: "complete", StartedAt: &start, Participants: participants}
+	for i, w := range wins {
+		t.Matches = append(t.Matches, &challonge.Match{Id: id*100 + i, State: "complete", PlayerOneId: w[0], PlayerTwoId: w[1], WinnerId: w[0]})
+	}
+	return t
+}
+
+func TestEloRatings(t *testing.T) {
+	week := time.Date(2026, 1, 5, 19, 0, 0, 0, time.UTC)
+	first := finished(1, week, []*challonge.Participant{
+		{Id: 11, Name: "Alice", Misc: "alice-01"}, {Id: 12, Name: "Bob", Misc: "bob-02"},
+	}, [][2]int{{11, 12}})
+	// a week later the same players enter under other names and ids
+	second := finished(2, week.AddDate(0, 0, 7), []*challonge.Participant{
+		{Id: 21, Name: "alice!", Misc: "alice-01"}, {Id: 22, Name: "bob", Misc: "bob-02"},
+		{Id: 23, Name: "Carol", Username: "carol"}, {Id: 24, Name: "Alice"},
+	}, [][2]int{{21, 23}, {23, 22}, {24, 22}})
+
+	engine := rating.NewEngine(nil)
+	if err := engine.AddAll([]*challonge.Tournament{second, first}); err != nil {
+		t.Fatalf("unable to rate.\nERR : %v\n", err)
+	}
+	alice := engine.Player("misc:alice-01")
+	if alice == nil || len(alice.History) != 2 || alice.Name != "alice!" {
+		t.Fatalf("expected alice to be linked across both tournaments, got %+v\n", alice)
+	}
+	if math.Abs(alice.History[0].Rating-1516) > 1e-9 || alice.History[0].TournamentId != 1 {
+		t.Errorf("expected 1516 after the first win, got %+v\n", alice.History[0])
+	}
+	// a name alone doesn't link, the second Alice and her match are left out
+	if engine.Player("name:alice") != nil || len(engine.Ranking()) != 3 {
+		t.Errorf("participants without misc or username should not be rated\n")
+	}
+	total := 0.0
+	for _, p := range engine.Ranking() {
+		total += p.Rating
+	}
+	if math.Abs(total-3*1500) > 1e-9 {
+		t.Errorf("elo should keep the rating total, got %f\n", total)
+	}
+	if ranking := engine.Ranking(); ranking[0] != alice || ranking[2].Key != "misc:bob-02" {
+		t.Errorf("unexpected ranking %s, %s, %s\n", ranking[0].Key, ranking[1].Key, ranking[2].Key)
+	}
+	if err := engine.Add(first); err == nil {
+		t.Errorf("a tournament should only be rated once\n")
+	}
+
+	// incremental update from saved ratings
+	saved := &bytes.Buffer{}
+	if err := engine.Save(saved); err != nil {
+		t.Fatalf("unable to save.\nERR : %v\n", err)
+	}
+	loaded, err := rating.Load(strings.NewReader(saved.String()), nil)
+	if err != nil {
+		t.Fatalf("unable to load.\nERR : %v\n", err)
+	}
+	third := finished(3, week.AddDate(0, 0, 14), []*challonge.Participant{
+		{Id: 31, Name: "Bob", Misc: "bob-02"}, {Id: 32, Name: "Alice", Misc: "alice-01"},
+	}, [][2]int{{31, 32}})
+	if err := loaded.AddAll([]*challonge.Tournament{first, second, third}); err != nil {
+		t.Fatalf("unable to rate.\nERR : %v\n", err)
+	}
+	bob := loaded.Player("misc:bob-02")
+	if len(bob.History) != 3 || bob.History[2].Change <= 0 || bob.Matches != 3 {
+		t.Errorf("expected bob to gain rating in the third tournament, got %+v\n", bob)
+	}
+	if _, err := rating.Load(strings.NewReader(saved.String()), &rating.Options{System: rating.Glicko2}); err == nil {
+		t.Errorf("loading elo ratings into glicko-2 should fail\n")
+	}
+}
+
+// TestGlicko2 follows the worked example of the Glicko-2 paper.
+func TestGlicko2(t *testing.T) {
+	saved := `{"system":"glicko2","players":[
+		{"key":"name:a","rating":1500,"deviation":200,"volatility":0.06},
+		{"key":"name:b","rating":1400,"deviation":30,"volatility":0.06},
+		{"key":"name:c","rating":1550,"deviation":100,"volatility":0.06},
+		{"key":"name:d","rating":1700,"deviation":300,"volatility":0.06},
+		{"key":"name:e","rating":1600,"deviation":50,"volatility":0.06}]}`
+	byName := func(p *challonge.Participant) string { return "name:" + p.Name }
+	engine, err := rating.Load(strings.NewReader(saved), &rating.Options{System: rating.Glicko2, Key: byName})
+	if err != nil {
+		t.Fatalf("unable to load.\nERR : %v\n", err)
+	}
+	// three separate tournaments would be three rating periods, so the paper's
+	// games are played in one
+	tournament := finished(1, time.Now(), []*challonge.Participant{
+		{Id: 1, Name: "a"}, {Id: 2, Name: "b"}, {Id: 3, Name: "c"}, {Id: 4, Name: "d"},
+	}, [][2]int{{1, 2}, {3, 1}, {4, 1}})
+	if err := engine.Add(tournament); err != nil {
+		t.Fatalf("unable to rate.\nERR : %v\n", err)
+	}
+	a := engine.Player("name:a")
+	if math.Abs(a.Rating-1464.06) > 0.01 || math.Abs(a.Deviation-151.52) > 0.01 || math.Abs(a.Volatility-0.05999) > 0.00001 {
+		t.Errorf("expected 1464.06 / 151.52 / 0.05999, got %.2f / %.2f / %.5f\n", a.Rating, a.Deviation, a.Volatility)
+	}
+	e := engine.Player("name:e")
+	if e.Rating != 1600 || e.Deviation <= 50 || len(e.History) != 0 {
+		t.Errorf("an idle player should only gain deviation, got %+v\n", e)
+	}
+}
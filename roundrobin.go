@@ -0,0 +1,119 @@
+package challonge
+
+import (
+	"fmt"
+	"sort"
+)
+
+// RoundRobinOptions configures GenerateRoundRobin.
+type RoundRobinOptions struct {
+	// Double plays every pairing twice, the second time with sides swapped.
+	Double bool
+}
+
+// GenerateRoundRobin schedules every pairing of the participants of t with
+// the circle method: the top seed stays in place while the others rotate,
+// giving rounds in which everyone plays at most once. With an odd count one
+// player sits out each round. Participants with a group id are scheduled
+// within their group, and the groups share round numbers. Match ids and
+// identifiers are numbered in play order.
+func GenerateRoundRobin(t *Tournament, opts *RoundRobinOptions) ([]*Match, error) {
+	if opts == nil {
+		opts = &RoundRobinOptions{}
+	}
+	groups := make(map[int][]*Participant)
+	keys := make([]int, 0)
+	for _, p := range t.Participants {
+		key := 0
+		if p.GroupId != nil {
+			key = *p.GroupId
+		}
+		if _, ok := groups[key]; !ok {
+			keys = append(keys, key)
+		}
+		groups[key] = append(groups[key], p)
+	}
+	sort.Ints(keys)
+
+	// rounds[r] holds the matches of round r+1 across all groups
+	rounds := make([][]*Match, 0)
+	for _, key := range keys {
+		players := groups[key]
+		if len(players) < 2 {
+			return nil, fmt.Errorf("a round robin needs at least 2 participants per group, got %d", len(players))
+		}
+		sort.SliceStable(players, func(i, j int) bool {
+			return players[i].Seed < players[j].Seed
+		})
+		var group *int
+		if key != 0 {
+			group = new(int)
+			*group = key
+		}
+		for r, pairs := range circle(players, opts.Double) {
+			if r == len(rounds) {
+				rounds = append(rounds, nil)
+			}
+			for _, pair := range pairs {
+				rounds[r] = append(rounds[r], &Match{
+					TournamentId: t.Id,
+					Round:        r + 1,
+					GroupId:      group,
+					State:        "open",
+					PlayerOneId:  pair[0].Id,
+					PlayerTwoId:  pair[1].Id,
+				})
+			}
+		}
+	}
+
+	matches := make([]*Match, 0)
+	for _, round := range rounds {
+		for _, m := range round {
+			m.Id = len(matches) + 1
+			m.Identifier = identifier(len(matches))
+			order := m.Id
+			m.SuggestedPlayOrder = &order
+			matches = append(matches, m)
+		}
+	}
+	return matches, nil
+}
+
+/** returns the pairings of each round, an odd field is padded with a nil player whose opponent sits out */
+func circle(players []*Participant, double bool) [][][2]*Participant {
+	ring := append([]*Participant{}, players...)
+	if len(ring)%2 == 1 {
+		ring = append(ring, nil)
+	}
+	n := len(ring)
+	rounds := make([][][2]*Participant, 0, n-1)
+	for r := 0; r < n-1; r++ {
+		pairs := make([][2]*Participant, 0, n/2)
+		for i := 0; i < n/2; i++ {
+			one, two := ring[i], ring[n-1-i]
+			// alternate sides so nobody is always player one
+			if (i == 0 && r%2 == 1) || (i > 0 && i%2 == 1) {
+				one, two = two, one
+			}
+			if one != nil && two != nil {
+				pairs = append(pairs, [2]*Participant{one, two})
+			}
+		}
+		rounds = append(rounds, pairs)
+		// keep the first player in place and rotate the others clockwise
+		last := ring[n-1]
+		copy(ring[2:], ring[1:n-1])
+		ring[1] = last
+	}
+	if double {
+		for r := 0; r < n-1; r++ {
+			swapped := make([][2]*Participant, 0, len(rounds[r]))
+			for _, pair := range rounds[r] {
+				swapped = append(swapped, [2]*Participant{pair[1], pair[0]})
+			}
+			rounds = append(rounds, swapped)
+		}
+	}
+	return rounds
+}
@@ -0,0 +1,98 @@
+package challonge
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// GameScore is the score of a single game within a match.
+type GameScore struct {
+	PlayerOne int
+	PlayerTwo int
+}
+
+// ParseScores parses a scores_csv value such as "3-1,1-3,3-2" into one
+// GameScore per game. Negative scores are written as "-1-3" or "3--1".
+func ParseScores(csv string) ([]GameScore, error) {
+	csv = strings.TrimSpace(csv)
+	if csv == "" {
+		return nil, nil
+	}
+	parts := strings.Split(csv, ",")
+	games := make([]GameScore, 0, len(parts))
+	for _, part := range parts {
+		part = strings.TrimSpace(part)
+		// the separator is the first dash that isn't a leading sign
+		i := strings.Index(part[min(1, len(part)):], "-") + 1
+		if i <= 0 {
+			return nil, fmt.Errorf("invalid game score %q", part)
+		}
+		one, err := strconv.Atoi(part[:i])
+		if err != nil {
+			return nil, fmt.Errorf("invalid game score %q", part)
+		}
+		two, err := strconv.Atoi(part[i+1:])
+		if err != nil {
+			return nil, fmt.Errorf("invalid game score %q", part)
+		}
+		games = append(games, GameScore{PlayerOne: one, PlayerTwo: two})
+	}
+	return games, nil
+}
+
+// FormatScores is the inverse of ParseScores.
+func FormatScores(games []GameScore) string {
+	parts := make([]string, 0, len(games))
+	for _, g := range games {
+		parts = append(parts, fmt.Sprintf("%d-%d", g.PlayerOne, g.PlayerTwo))
+	}
+	return strings.Join(parts, ",")
+}
+
+/** returns the points of a single game, or the games won by each player over several games */
+func gameTotals(games []GameScore) (int, int) {
+	one, two := 0, 0
+	for _, g := range games {
+		if len(games) == 1 {
+			one, two = g.PlayerOne, g.PlayerTwo
+		} else if g.PlayerOne > g.PlayerTwo {
+			one++
+		} else if g.PlayerTwo > g.PlayerOne {
+			two++
+		}
+	}
+	return one, two
+}
+
+/** returns the winner of games, or 0 on a tie */
+func inferWinner(playerOne int, playerTwo int, games []GameScore) int {
+	one, two := gameTotals(games)
+	if one > two {
+		return playerOne
+	} else if two > one {
+		return playerTwo
+	}
+	return 0
+}
+
+/** returns the winner implied by the game scores, 0 meaning a tie */
+func (m *Match) InferWinner() int {
+	return inferWinner(m.PlayerOneId, m.PlayerTwoId, m.Games)
+}
+
+/** replaces the game scores of m, e.g. "3-1,1-3,3-2" */
+func (m *Match) SetScores(csv string) error {
+	games, err := ParseScores(csv)
+	if err != nil {
+		return err
+	}
+	m.Games = games
+	m.Scores = FormatScores(games)
+	m.PlayerOneScore, m.PlayerTwoScore = gameTotals(games)
+	return nil
+}
+
+func (m *Match) IsTie() bool {
+	return m.State == "complete" && m.WinnerId == 0
+}
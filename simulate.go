@@ -0,0 +1,303 @@
+package challonge
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+)
+
+const (
+	defaultSimulationRuns = 10000
+	defaultElo            = 1500
+)
+
+// WinProbability returns the probability that one beats two.
+type WinProbability func(one *Participant, two *Participant) float64
+
+// EloWinProbability is the Elo expected score of one against two. Players
+// missing from ratings are rated 1500.
+func EloWinProbability(ratings map[int]float64) WinProbability {
+	rating := func(p *Participant) float64 {
+		if r, ok := ratings[p.Id]; ok {
+			return r
+		}
+		return defaultElo
+	}
+	return func(one *Participant, two *Participant) float64 {
+		return 1 / (1 + math.Pow(10, (rating(two)-rating(one))/400))
+	}
+}
+
+// SimulationOptions configures Simulate.
+type SimulationOptions struct {
+	// Runs defaults to 10000.
+	Runs int
+	// Seed makes the simulation reproducible.
+	Seed int64
+	// Model defaults to EloWinProbability over Ratings.
+	Model   WinProbability
+	Ratings map[int]float64
+}
+
+// SimulationResult holds the outcome probabilities found by Simulate.
+type SimulationResult struct {
+	Runs int
+	// Placements maps a participant id to the probability of each final rank.
+	Placements map[int]map[int]float64
+	// MatchWins maps the id of every match not yet complete to the
+	// probability of each participant winning it.
+	MatchWins map[int]map[int]float64
+}
+
+/** returns the probability of participant id finishing first */
+func (r *SimulationResult) WinProbability(id int) float64 {
+	return r.Placements[id][1]
+}
+
+// Simulate plays the remaining matches of t many times over and counts the
+// outcomes. Completed matches keep their result. In elimination brackets
+// players place by the round they are knocked out in and the grand final
+// reset is only played when the losers bracket finalist wins the first
+// grand final. Round robin and swiss tournaments are ranked with Standings.
+func Simulate(t *Tournament, opts *SimulationOptions) (*SimulationResult, error) {
+	o := SimulationOptions{}
+	if opts != nil {
+		o = *opts
+	}
+	if o.Runs <= 0 {
+		o.Runs = defaultSimulationRuns
+	}
+	if o.Model == nil {
+		o.Model = EloWinProbability(o.Ratings)
+	}
+	bracket := NewBracket(t)
+	order, err := playOrder(bracket)
+	if err != nil {
+		return nil, err
+	}
+	elimination := bracket.GrandFinal() != nil
+
+	placements := make(map[int]map[int]int)
+	wins := make(map[int]map[int]int)
+	rng := rand.New(rand.NewSource(o.Seed))
+	for run := 0; run < o.Runs; run++ {
+		outcome := simulateRun(t, bracket, order, o.Model, rng)
+		for _, m := range order {
+			if m.State == "complete" {
+				continue
+			}
+			if w := outcome[m.Id].winner; w != 0 {
+				if wins[m.Id] == nil {
+					wins[m.Id] = make(map[int]int)
+				}
+				wins[m.Id][w]++
+			}
+		}
+		var ranks map[int]int
+		if elimination {
+			ranks = eliminationRanks(t, bracket, outcome)
+		} else {
+			ranks = standingsRanks(t, outcome)
+		}
+		for id, rank := range ranks {
+			if placements[id] == nil {
+				placements[id] = make(map[int]int)
+			}
+			placements[id][rank]++
+		}
+	}
+
+	result := &SimulationResult{
+		Runs:       o.Runs,
+		Placements: make(map[int]map[int]float64, len(placements)),
+		MatchWins:  make(map[int]map[int]float64, len(wins)),
+	}
+	for id, counts := range placements {
+		result.Placements[id] = make(map[int]float64, len(counts))
+		for rank, n := range counts {
+			result.Placements[id][rank] = float64(n) / float64(o.Runs)
+		}
+	}
+	for id, counts := range wins {
+		result.MatchWins[id] = make(map[int]float64, len(counts))
+		for p, n := range counts {
+			result.MatchWins[id][p] = float64(n) / float64(o.Runs)
+		}
+	}
+	return result, nil
+}
+
+/** the players and result of a match in a single run */
+type simulated struct {
+	one, two      int
+	winner, loser int
+	played        bool
+}
+
+/** orders matches so that every match comes after the ones feeding it */
+func playOrder(b *Bracket) ([]*Match, error) {
+	order := make([]*Match, 0, len(b.Matches()))
+	state := make(map[int]int) // 1 visiting, 2 done
+	var visit func(m *Match) error
+	visit = func(m *Match) error {
+		switch state[m.Id] {
+		case 1:
+			return fmt.Errorf("match %d depends on itself", m.Id)
+		case 2:
+			return nil
+		}
+		state[m.Id] = 1
+		for _, p := range b.Prerequisites(m.Id) {
+			if err := visit(p); err != nil {
+				return err
+			}
+		}
+		state[m.Id] = 2
+		order = append(order, m)
+		return nil
+	}
+	for _, m := range b.Matches() {
+		if err := visit(m); err != nil {
+			return nil, err
+		}
+	}
+	return order, nil
+}
+
+func simulateRun(t *Tournament, b *Bracket, order []*Match, model WinProbability, rng *rand.Rand) map[int]*simulated {
+	outcome := make(map[int]*simulated, len(order))
+	player := func(id int, prereq *int, loser bool) int {
+		if id != 0 || prereq == nil || outcome[*prereq] == nil {
+			return id
+		}
+		if loser {
+			return outcome[*prereq].loser
+		}
+		return outcome[*prereq].winner
+	}
+	for _, m := range order {
+		s := &simulated{
+			one: player(m.PlayerOneId, m.PlayerOnePrereqMatch, m.PlayerOneIsPrereqMatchLoser),
+			two: player(m.PlayerTwoId, m.PlayerTwoPrereqMatch, m.PlayerTwoIsPrereqMatchLoser),
+		}
+		outcome[m.Id] = s
+		if b.IsGrandFinalReset(m.Id) {
+			// only played when the losers bracket finalist took the first grand final
+			gf := b.Match(*m.PlayerOnePrereqMatch)
+			if outcome[gf.Id].winner != losersFinalist(b, gf, outcome[gf.Id]) {
+				continue
+			}
+		}
+		switch {
+		case m.State == "complete" && m.WinnerId != 0:
+			s.winner = m.WinnerId
+			s.loser = s.one
+			if s.loser == s.winner {
+				s.loser = s.two
+			}
+		case m.State == "complete":
+			// a tie keeps both players where they are
+		case s.one == 0 || s.two == 0:
+			s.winner = s.one + s.two
+		default:
+			one, two := t.GetParticipant(s.one), t.GetParticipant(s.two)
+			p := 0.5
+			if one != nil && two != nil {
+				p = model(one, two)
+			}
+			if rng.Float64() < p {
+				s.winner, s.loser = s.one, s.two
+			} else {
+				s.winner, s.loser = s.two, s.one
+			}
+		}
+		s.played = true
+	}
+	return outcome
+}
+
+/** returns the grand final player who came through the losers bracket */
+func losersFinalist(b *Bracket, gf *Match, s *simulated) int {
+	if gf.PlayerOnePrereqMatch != nil && b.IsLosersBracket(*gf.PlayerOnePrereqMatch) {
+		return s.one
+	}
+	if gf.PlayerTwoPrereqMatch != nil && b.IsLosersBracket(*gf.PlayerTwoPrereqMatch) {
+		return s.two
+	}
+	return 0
+}
+
+// eliminationRanks places every player by how far they got: the champion
+// first, then by the round they were knocked out in, later rounds placing
+// higher and players knocked out in the same round sharing a rank.
+func eliminationRanks(t *Tournament, b *Bracket, outcome map[int]*simulated) map[int]int {
+	level := make(map[int]float64, len(t.Participants))
+	for _, m := range b.Matches() {
+		s := outcome[m.Id]
+		if s == nil || !s.played || s.loser == 0 || b.LoserDropsTo(m.Id) != nil {
+			continue
+		}
+		if b.IsGrandFinalReset(m.Id) {
+			continue
+		}
+		round := math.Abs(float64(m.Round))
+		if b.IsThirdPlaceMatch(m.Id) {
+			level[s.winner] = round - 0.25
+			level[s.loser] = round - 0.5
+			continue
+		}
+		if m.Round < 0 {
+			// losers bracket rounds rank below the grand final
+			round = round - 1000
+		}
+		level[s.loser] = round
+	}
+	final := b.GrandFinal()
+	champion := 0
+	if reset := b.GrandFinalReset(); reset != nil && outcome[reset.Id].played {
+		s := outcome[reset.Id]
+		champion = s.winner
+		level[s.loser] = math.MaxFloat64 / 2
+	} else if final != nil && outcome[final.Id] != nil {
+		s := outcome[final.Id]
+		champion = s.winner
+		level[s.loser] = math.MaxFloat64 / 2
+	}
+	level[champion] = math.MaxFloat64
+
+	ranks := make(map[int]int, len(level))
+	for id, l := range level {
+		if id == 0 {
+			continue
+		}
+		rank := 1
+		for _, other := range level {
+			if other > l {
+				rank++
+			}
+		}
+		ranks[id] = rank
+	}
+	return ranks
+}
+
+/** ranks a round robin or swiss tournament as if the simulated results had been reported */
+func standingsRanks(t *Tournament, outcome map[int]*simulated) map[int]int {
+	copied := *t
+	copied.Matches = make([]*Match, 0, len(t.Matches))
+	for _, m := range t.Matches {
+		s := outcome[m.Id]
+		if s == nil || !s.played {
+			continue
+		}
+		played := *m
+		played.State = "complete"
+		played.WinnerId = s.winner
+		copied.Matches = append(copied.Matches, &played)
+	}
+	ranks := make(map[int]int, len(t.Participants))
+	for _, s := range copied.Standings(nil) {
+		ranks[s.Participant.Id] = s.Rank
+	}
+	return ranks
+}
@@ -0,0 +1,256 @@
+package challonge
+
+import (
+	"sort"
+	"strconv"
+)
+
+// TieBreak names a criterion used to order participants with equal points.
+type TieBreak string
+
+const (
+	TieBreakMatchWins        TieBreak = "match wins"
+	TieBreakGameWins         TieBreak = "game wins"
+	TieBreakPointsDifference TieBreak = "points difference"
+	TieBreakHeadToHead       TieBreak = "head to head"
+	TieBreakBuchholz         TieBreak = "buchholz"
+	TieBreakMedianBuchholz   TieBreak = "median buchholz"
+	TieBreakSonnebornBerger  TieBreak = "sonneborn berger"
+)
+
+// StandingsOptions configures Standings.
+type StandingsOptions struct {
+	PointsForWin float64
+	PointsForTie float64
+	PointsForBye float64
+
+	// TieBreaks are applied in order to participants with equal points.
+	TieBreaks []TieBreak
+}
+
+// DefaultStandingsOptions reads the point values configured on t, falling
+// back to 1 for a win or bye and 0.5 for a tie.
+func DefaultStandingsOptions(t *Tournament) *StandingsOptions {
+	win, tie, bye := t.PtsForMatchWin, t.PtsForMatchTie, t.PtsForBye
+	if t.Type == "round robin" {
+		win, tie = t.RrPtsForMatchWin, t.RrPtsForMatchTie
+	}
+	return &StandingsOptions{
+		PointsForWin: parsePoints(win, 1),
+		PointsForTie: parsePoints(tie, 0.5),
+		PointsForBye: parsePoints(bye, 1),
+		TieBreaks: []TieBreak{
+			TieBreakHeadToHead,
+			TieBreakBuchholz,
+			TieBreakMedianBuchholz,
+			TieBreakSonnebornBerger,
+			TieBreakGameWins,
+			TieBreakPointsDifference,
+		},
+	}
+}
+
+func parsePoints(s string, fallback float64) float64 {
+	if f, err := strconv.ParseFloat(s, 64); err == nil {
+		return f
+	}
+	return fallback
+}
+
+// Standing is the line of a single participant in the standings.
+type Standing struct {
+	Participant *Participant
+	// Rank is shared by participants no tie-break could separate.
+	Rank   int
+	Points float64
+
+	MatchWins   int
+	MatchLosses int
+	MatchTies   int
+	Byes        int
+
+	GameWins      int
+	GameLosses    int
+	PointsFor     int
+	PointsAgainst int
+
+	Buchholz        float64
+	MedianBuchholz  float64
+	SonnebornBerger float64
+
+	opponents []*Standing
+	results   []float64 // 1 for a win, 0.5 for a tie, 0 for a loss, per opponent
+}
+
+func (s *Standing) PointsDifference() int {
+	return s.PointsFor - s.PointsAgainst
+}
+
+// Standings ranks the participants of t from its completed matches. Byes
+// are counted for swiss tournaments, for every round a participant has no
+// match in, whether played or not. A nil opts uses DefaultStandingsOptions.
+func (t *Tournament) Standings(opts *StandingsOptions) []*Standing {
+	if opts == nil {
+		opts = DefaultStandingsOptions(t)
+	}
+	standings := make([]*Standing, 0, len(t.Participants))
+	byId := make(map[int]*Standing, len(t.Participants))
+	for _, p := range t.Participants {
+		s := &Standing{Participant: p}
+		standings = append(standings, s)
+		byId[p.Id] = s
+	}
+
+	rounds := make(map[int]map[int]bool)
+	for _, m := range t.Matches {
+		one, two := byId[m.PlayerOneId], byId[m.PlayerTwoId]
+		if one == nil || two == nil {
+			continue
+		}
+		// a match still to be played isn't a bye
+		if rounds[m.Round] == nil {
+			rounds[m.Round] = make(map[int]bool)
+		}
+		rounds[m.Round][one.Participant.Id] = true
+		rounds[m.Round][two.Participant.Id] = true
+		if m.State != "complete" {
+			continue
+		}
+
+		var result float64
+		switch m.WinnerId {
+		case one.Participant.Id:
+			one.MatchWins++
+			two.MatchLosses++
+			result = 1
+		case two.Participant.Id:
+			two.MatchWins++
+			one.MatchLosses++
+			result = 0
+		default:
+			one.MatchTies++
+			two.MatchTies++
+			result = 0.5
+		}
+		one.opponents, one.results = append(one.opponents, two), append(one.results, result)
+		two.opponents, two.results = append(two.opponents, one), append(two.results, 1-result)
+
+		for _, g := range m.Games {
+			one.PointsFor += g.PlayerOne
+			one.PointsAgainst += g.PlayerTwo
+			two.PointsFor += g.PlayerTwo
+			two.PointsAgainst += g.PlayerOne
+			if g.PlayerOne > g.PlayerTwo {
+				one.GameWins++
+				two.GameLosses++
+			} else if g.PlayerTwo > g.PlayerOne {
+				two.GameWins++
+				one.GameLosses++
+			}
+		}
+	}
+	if t.Type == "swiss" {
+		for _, played := range rounds {
+			for _, s := range standings {
+				if !played[s.Participant.Id] && s.Participant.Active {
+					s.Byes++
+				}
+			}
+		}
+	}
+
+	for _, s := range standings {
+		s.Points = float64(s.MatchWins)*opts.PointsForWin + float64(s.MatchTies)*opts.PointsForTie + float64(s.Byes)*opts.PointsForBye
+	}
+	for _, s := range standings {
+		scores := make([]float64, 0, len(s.opponents))
+		for i, o := range s.opponents {
+			s.Buchholz += o.Points
+			s.SonnebornBerger += o.Points * s.results[i]
+			scores = append(scores, o.Points)
+		}
+		s.MedianBuchholz = s.Buchholz
+		if len(scores) > 2 {
+			sort.Float64s(scores)
+			s.MedianBuchholz -= scores[0] + scores[len(scores)-1]
+		}
+	}
+
+	sort.SliceStable(standings, func(i, j int) bool {
+		return standings[i].Participant.Seed < standings[j].Participant.Seed
+	})
+	groups := splitBy(standings, func(_ []*Standing, s *Standing) float64 { return s.Points })
+	for _, tb := range opts.TieBreaks {
+		refined := make([][]*Standing, 0, len(groups))
+		for _, g := range groups {
+			if len(g) == 1 {
+				refined = append(refined, g)
+				continue
+			}
+			refined = append(refined, splitBy(g, tb.value)...)
+		}
+		groups = refined
+	}
+
+	ranked := make([]*Standing, 0, len(standings))
+	for _, g := range groups {
+		rank := len(ranked) + 1
+		for _, s := range g {
+			s.Rank = rank
+			ranked = append(ranked, s)
+		}
+	}
+	return ranked
+}
+
+/** sorts group by value, highest first, and splits it where the value changes */
+func splitBy(group []*Standing, value func([]*Standing, *Standing) float64) [][]*Standing {
+	values := make(map[*Standing]float64, len(group))
+	for _, s := range group {
+		values[s] = value(group, s)
+	}
+	sort.SliceStable(group, func(i, j int) bool {
+		return values[group[i]] > values[group[j]]
+	})
+	groups := make([][]*Standing, 0)
+	start := 0
+	for i := 1; i <= len(group); i++ {
+		if i == len(group) || values[group[i]] != values[group[start]] {
+			groups = append(groups, group[start:i])
+			start = i
+		}
+	}
+	return groups
+}
+
+/** returns the tie-break value of s within the group of participants it is tied with */
+func (tb TieBreak) value(group []*Standing, s *Standing) float64 {
+	switch tb {
+	case TieBreakMatchWins:
+		return float64(s.MatchWins)
+	case TieBreakGameWins:
+		return float64(s.GameWins)
+	case TieBreakPointsDifference:
+		return float64(s.PointsDifference())
+	case TieBreakBuchholz:
+		return s.Buchholz
+	case TieBreakMedianBuchholz:
+		return s.MedianBuchholz
+	case TieBreakSonnebornBerger:
+		return s.SonnebornBerger
+	case TieBreakHeadToHead:
+		// results against the other tied participants only
+		tied := make(map[*Standing]bool, len(group))
+		for _, g := range group {
+			tied[g] = true
+		}
+		var total float64
+		for i, o := range s.opponents {
+			if tied[o] {
+				total += s.results[i]
+			}
+		}
+		return total
+	}
+	return 0
+}
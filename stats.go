@@ -0,0 +1,65 @@
+package challonge
+
+// ParticipantStats are statistics derived from the matches of a tournament,
+// kept apart from the participant data returned by the API.
+type ParticipantStats struct {
+	ParticipantId int
+	Wins          int
+	Losses        int
+	Ties          int
+	TotalScore    int
+}
+
+// ComputeStats counts wins, losses, ties and scores per participant over
+// the completed matches. It doesn't modify the matches, so calling it again
+// on the same matches gives the same result.
+func ComputeStats(matches []*Match) map[int]*ParticipantStats {
+	stats := make(map[int]*ParticipantStats)
+	get := func(id int) *ParticipantStats {
+		s, ok := stats[id]
+		if !ok {
+			s = &ParticipantStats{ParticipantId: id}
+			stats[id] = s
+		}
+		return s
+	}
+	for _, m := range matches {
+		if m == nil || m.State != "complete" || m.PlayerOneId == 0 || m.PlayerTwoId == 0 {
+			continue
+		}
+		one, two := get(m.PlayerOneId), get(m.PlayerTwoId)
+		switch m.WinnerId {
+		case m.PlayerOneId:
+			one.Wins++
+			two.Losses++
+		case m.PlayerTwoId:
+			two.Wins++
+			one.Losses++
+		default:
+			one.Ties++
+			two.Ties++
+		}
+		one.TotalScore += m.PlayerOneScore
+		two.TotalScore += m.PlayerTwoScore
+	}
+	return stats
+}
+
+/** returns the statistics of every participant of t, including those without matches */
+func (t *Tournament) ParticipantStats() map[int]*ParticipantStats {
+	stats := ComputeStats(t.Matches)
+	for _, p := range t.Participants {
+		if _, ok := stats[p.Id]; !ok {
+			stats[p.Id] = &ParticipantStats{ParticipantId: p.Id}
+		}
+	}
+	return stats
+}
+
+/** returns the statistics of a single participant */
+func (t *Tournament) GetStats(id int) *ParticipantStats {
+	if s, ok := ComputeStats(t.Matches)[id]; ok {
+		return s
+	}
+	return &ParticipantStats{ParticipantId: id}
+}
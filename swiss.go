@@ -0,0 +1,265 @@
+package challonge
+
+import (
+	"fmt"
+	"sort"
+)
+
+// SwissOptions configures PairSwiss.
+type SwissOptions struct {
+	// AllowRematches lets players meet again; otherwise a rematch is only
+	// proposed when no other pairing exists, and flagged as such.
+	AllowRematches bool
+	// KeepScoreGroups only pairs players of the same or neighbouring score
+	// groups. Players of the same group are preferred, but any number of
+	// them may float to the next group when they can't be paired within it.
+	KeepScoreGroups bool
+	// Team returns the team of a participant; players of the same non-empty
+	// team are never paired.
+	Team func(*Participant) string
+	// Standings ranks the players, nil uses DefaultStandingsOptions.
+	Standings *StandingsOptions
+}
+
+// SwissPairing is a proposed match. PlayerTwo is nil for a bye.
+type SwissPairing struct {
+	PlayerOne *Participant
+	PlayerTwo *Participant
+	// Reasons explain why the pairing was made, and which opponents were
+	// passed over and why.
+	Reasons []string
+}
+
+// SwissRound is the pairing proposed for the next round.
+type SwissRound struct {
+	Round    int
+	Pairings []*SwissPairing
+}
+
+type swissPlayer struct {
+	standing *Standing
+	rank     int
+	group    int // score group index, 0 being the highest
+	team     string
+	played   map[int]bool
+}
+
+// PairSwiss proposes the next swiss round from the completed matches of t.
+// Active players are ranked with Standings and split into score groups.
+// With an odd count the lowest ranked player without a bye sits out. The
+// rest are paired from the top down, each player preferably meeting the
+// player half a score group below them, backtracking whenever a later
+// player would be left without a valid opponent. When rematches can't be
+// avoided, the pairing with the fewest of them is proposed.
+func (t *Tournament) PairSwiss(opts *SwissOptions) (*SwissRound, error) {
+	if opts == nil {
+		opts = &SwissOptions{}
+	}
+	players := make([]*swissPlayer, 0, len(t.Participants))
+	byId := make(map[int]*swissPlayer)
+	for _, s := range t.Standings(opts.Standings) {
+		if !s.Participant.Active {
+			continue
+		}
+		p := &swissPlayer{standing: s, rank: len(players), played: make(map[int]bool)}
+		if opts.Team != nil {
+			p.team = opts.Team(s.Participant)
+		}
+		players = append(players, p)
+		byId[s.Participant.Id] = p
+	}
+	round := &SwissRound{Round: 1}
+	for _, m := range t.Matches {
+		if m.Round >= round.Round {
+			round.Round = m.Round + 1
+		}
+		if one, two := byId[m.PlayerOneId], byId[m.PlayerTwoId]; one != nil && two != nil {
+			one.played[two.standing.Participant.Id] = true
+			two.played[one.standing.Participant.Id] = true
+		}
+	}
+	for i, p := range players {
+		if i > 0 && p.standing.Points != players[i-1].standing.Points {
+			p.group = players[i-1].group + 1
+		} else if i > 0 {
+			p.group = players[i-1].group
+		}
+	}
+
+	if len(players)%2 == 1 {
+		byeAt := len(players) - 1
+		for i := len(players) - 1; i >= 0; i-- {
+			if players[i].standing.Byes == 0 {
+				byeAt = i
+				break
+			}
+		}
+		p := players[byeAt]
+		reason := "lowest ranked player without a bye"
+		if p.standing.Byes > 0 {
+			reason = "every player already had a bye, lowest ranked sits out"
+		}
+		round.Pairings = append(round.Pairings, &SwissPairing{PlayerOne: p.standing.Participant, Reasons: []string{reason}})
+		players = append(players[:byeAt:byeAt], players[byeAt+1:]...)
+	}
+
+	// allow one more rematch at a time, so the pairing found has the fewest
+	s := &swissSolver{players: players, opts: opts, paired: make(map[*swissPlayer]bool)}
+	for budget := 0; !s.solve(budget); budget++ {
+		if opts.AllowRematches || budget >= len(players)/2 {
+			return nil, fmt.Errorf("no pairing satisfies the constraints for %d players", len(players))
+		}
+	}
+	pairings := s.pairings
+	sort.SliceStable(pairings, func(i, j int) bool {
+		return byId[pairings[i].PlayerOne.Id].rank < byId[pairings[j].PlayerOne.Id].rank
+	})
+	round.Pairings = append(pairings, round.Pairings...)
+	return round, nil
+}
+
+/** upper bound on pairing attempts before giving up */
+const maxSwissSteps = 1000000
+
+type swissSolver struct {
+	players  []*swissPlayer
+	opts     *SwissOptions
+	paired   map[*swissPlayer]bool
+	pairings []*SwissPairing
+	steps    int
+	// budget is the number of rematches allowed, rematches those made so far
+	budget    int
+	rematches int
+	// capped is set once a search gave up at maxSwissSteps
+	capped bool
+}
+
+/** pairs every player with at most budget rematches, unless rematches are allowed */
+func (s *swissSolver) solve(budget int) bool {
+	s.paired = make(map[*swissPlayer]bool)
+	s.pairings = nil
+	s.steps = 0
+	s.budget = budget
+	s.rematches = 0
+	return s.pairNext()
+}
+
+func (s *swissSolver) pairNext() bool {
+	var top *swissPlayer
+	for _, p := range s.players {
+		if !s.paired[p] {
+			top = p
+			break
+		}
+	}
+	if top == nil {
+		return true
+	}
+	s.paired[top] = true
+
+	reasons := make([]string, 0)
+	for _, c := range s.candidates(top) {
+		if why := s.conflict(top, c); why != "" {
+			reasons = append(reasons, fmt.Sprintf("passed over %s: %s", c.standing.Participant.Name, why))
+			continue
+		}
+		if s.steps++; s.steps > maxSwissSteps {
+			s.capped = true
+			break
+		}
+		rematch := top.played[c.standing.Participant.Id]
+		if rematch {
+			s.rematches++
+		}
+		s.paired[c] = true
+		pairing := &SwissPairing{PlayerOne: top.standing.Participant, PlayerTwo: c.standing.Participant}
+		pairing.Reasons = append(append([]string{}, reasons...), s.explain(top, c)...)
+		s.pairings = append(s.pairings, pairing)
+		if s.pairNext() {
+			return true
+		}
+		s.pairings = s.pairings[:len(s.pairings)-1]
+		s.paired[c] = false
+		if rematch {
+			s.rematches--
+		}
+		reasons = append(reasons, fmt.Sprintf("passed over %s: would leave other players unpaired", c.standing.Participant.Name))
+	}
+	s.paired[top] = false
+	return false
+}
+
+// candidates orders the opponents of p: their own score group first,
+// starting half way down the group, then the groups below.
+func (s *swissSolver) candidates(p *swissPlayer) []*swissPlayer {
+	group := make([]*swissPlayer, 0)
+	others := make([]*swissPlayer, 0)
+	for _, c := range s.players {
+		if s.paired[c] {
+			continue
+		}
+		if c.group == p.group {
+			group = append(group, c)
+		} else {
+			others = append(others, c)
+		}
+	}
+	ideal := len(group) / 2
+	distance := func(i int) int {
+		if i >= ideal {
+			return (i - ideal) * 2
+		}
+		return (ideal-i)*2 - 1
+	}
+	index := make(map[*swissPlayer]int, len(group))
+	for i, c := range group {
+		index[c] = i
+	}
+	sort.SliceStable(group, func(i, j int) bool {
+		return distance(index[group[i]]) < distance(index[group[j]])
+	})
+	sort.SliceStable(others, func(i, j int) bool {
+		return abs(others[i].group-p.group) < abs(others[j].group-p.group)
+	})
+	return append(group, others...)
+}
+
+/** returns why p and c can't be paired, or an empty string */
+func (s *swissSolver) conflict(p *swissPlayer, c *swissPlayer) string {
+	if p.team != "" && p.team == c.team {
+		return "same team " + p.team
+	}
+	if p.played[c.standing.Participant.Id] && !s.opts.AllowRematches && s.rematches >= s.budget {
+		return "already played"
+	}
+	if s.opts.KeepScoreGroups && abs(p.group-c.group) > 1 {
+		return "score groups too far apart"
+	}
+	return ""
+}
+
+func (s *swissSolver) explain(p *swissPlayer, c *swissPlayer) []string {
+	reasons := make([]string, 0, 2)
+	if p.group == c.group {
+		reasons = append(reasons, fmt.Sprintf("same score group (%g points)", p.standing.Points))
+	} else {
+		reasons = append(reasons, fmt.Sprintf("floated from %g to %g points", p.standing.Points, c.standing.Points))
+	}
+	switch {
+	case !p.played[c.standing.Participant.Id]:
+	case s.opts.AllowRematches:
+		reasons = append(reasons, "rematch, rematches are allowed")
+	case s.capped:
+		reasons = append(reasons, "rematch, the search limit was reached before a pairing with fewer rematches was found")
+	default:
+		reasons = append(reasons, fmt.Sprintf("rematch, every pairing needs at least %d", s.budget))
+	}
+	return reasons
+}
+
+func abs(i int) int {
+	if i < 0 {
+		return -i
+	}
+	return i
+}
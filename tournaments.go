@@ -0,0 +1,103 @@
+package challonge
+
+import (
+	"context"
+	"iter"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+const dateLayout = "2006-01-02"
+
+// TournamentQuery holds the filters accepted by the tournament index.
+// Zero values are left out of the request.
+type TournamentQuery struct {
+	State         string // all, pending, in_progress, ended
+	Type          string // single elimination, double elimination, round robin, swiss
+	Subdomain     string
+	CreatedAfter  time.Time
+	CreatedBefore time.Time
+
+	// Page is the first page to fetch, starting at 1.
+	Page int
+	// PerPage is the expected page size; a shorter page ends iteration.
+	PerPage int
+}
+
+func (q TournamentQuery) values() url.Values {
+	v := url.Values{}
+	if q.State != "" {
+		v.Set("state", q.State)
+	}
+	if q.Type != "" {
+		v.Set("type", q.Type)
+	}
+	if q.Subdomain != "" {
+		v.Set("subdomain", q.Subdomain)
+	}
+	if !q.CreatedAfter.IsZero() {
+		v.Set("created_after", q.CreatedAfter.Format(dateLayout))
+	}
+	if !q.CreatedBefore.IsZero() {
+		v.Set("created_before", q.CreatedBefore.Format(dateLayout))
+	}
+	if q.Page > 0 {
+		v.Set("page", strconv.Itoa(q.Page))
+	}
+	if q.PerPage > 0 {
+		v.Set("per_page", strconv.Itoa(q.PerPage))
+	}
+	return v
+}
+
+// ListTournaments fetches a single page of tournaments matching q.
+func (c *Client) ListTournaments(ctx context.Context, q TournamentQuery) ([]*Tournament, error) {
+	url := c.buildUrl("tournaments", q.values())
+	response := []GetTournamentsResponse{}
+	if err := doRequest(ctx, "GET", url, "", nil, &response); err != nil {
+		return nil, err
+	}
+	tournaments := make([]*Tournament, 0, len(response))
+	for i := 0; i < len(response); i++ {
+		tournaments = append(tournaments, response[i].Tournament)
+	}
+	return tournaments, nil
+}
+
+// Tournaments lazily walks every page of tournaments matching q, starting at
+// q.Page. Iteration stops at the first empty page, on a request error or when
+// ctx is cancelled; errors are yielded once with a nil tournament.
+func (c *Client) Tournaments(ctx context.Context, q TournamentQuery) iter.Seq2[*Tournament, error] {
+	return func(yield func(*Tournament, error) bool) {
+		if q.Page < 1 {
+			q.Page = 1
+		}
+		lastFirst := 0
+		for {
+			if err := ctx.Err(); err != nil {
+				yield(nil, err)
+				return
+			}
+			tournaments, err := c.ListTournaments(ctx, q)
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			// the API hands back the same list again when paging is unsupported
+			if len(tournaments) == 0 || tournaments[0].Id == lastFirst {
+				return
+			}
+			lastFirst = tournaments[0].Id
+			for _, t := range tournaments {
+				if !yield(t, nil) {
+					return
+				}
+			}
+			if q.PerPage > 0 && len(tournaments) < q.PerPage {
+				return
+			}
+			q.Page++
+		}
+	}
+}
@@ -0,0 +1,121 @@
+package challonge
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+const (
+	defaultWatchInterval   = 30 * time.Second
+	defaultWatchMaxBackoff = 5 * time.Minute
+)
+
+// WatchOptions configures Watch and WatchFunc. The zero value polls every
+// 30 seconds without jitter and backs off up to 5 minutes on errors.
+type WatchOptions struct {
+	Interval time.Duration
+	// Jitter randomizes every wait by up to this fraction, e.g. 0.1 for ±10%.
+	Jitter float64
+	// MaxBackoff caps the wait after consecutive failed polls.
+	MaxBackoff time.Duration
+	// OnError is called for every failed poll.
+	OnError func(error)
+	// Buffer is the capacity of the channel returned by Watch.
+	Buffer int
+}
+
+// Watch polls a tournament and sends what changed between two polls on the
+// returned channel, which is closed once ctx is done or the tournament can
+// no longer be fetched. Once the channel is closed, the returned function
+// gives the reason, as returned by WatchFunc.
+func (c *Client) Watch(ctx context.Context, tournament string, opts *WatchOptions) (<-chan Event, func() error) {
+	buffer := 0
+	if opts != nil {
+		buffer = opts.Buffer
+	}
+	events := make(chan Event, buffer)
+	var mu sync.Mutex
+	var stopped error
+	go func() {
+		defer close(events)
+		err := c.WatchFunc(ctx, tournament, opts, func(e Event) {
+			select {
+			case events <- e:
+			case <-ctx.Done():
+			}
+		})
+		mu.Lock()
+		stopped = err
+		mu.Unlock()
+	}()
+	return events, func() error {
+		mu.Lock()
+		defer mu.Unlock()
+		return stopped
+	}
+}
+
+// WatchFunc polls a tournament and calls fn with every event until ctx is
+// done. The first poll only sets the baseline. A failed poll is retried
+// with exponential backoff and the next successful one is compared with the
+// last good snapshot, so no event is lost or delivered twice. WatchFunc
+// returns ctx.Err() or, when the API refuses the request for good, that error.
+func (c *Client) WatchFunc(ctx context.Context, tournament string, opts *WatchOptions, fn func(Event)) error {
+	o := WatchOptions{}
+	if opts != nil {
+		o = *opts
+	}
+	if o.Interval <= 0 {
+		o.Interval = defaultWatchInterval
+	}
+	if o.MaxBackoff < o.Interval {
+		o.MaxBackoff = max(defaultWatchMaxBackoff, o.Interval)
+	}
+
+	var last *Tournament
+	wait := time.Duration(0)
+	for {
+		if wait > 0 {
+			timer := time.NewTimer(jitter(wait, o.Jitter))
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return ctx.Err()
+			case <-timer.C:
+			}
+		}
+		current, err := c.NewTournamentRequest(tournament).WithParticipants().WithMatches().GetContext(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			if o.OnError != nil {
+				o.OnError(err)
+			}
+			var apiErr *APIError
+			if errors.As(err, &apiErr) && !apiErr.Temporary() {
+				return err
+			}
+			wait = min(max(wait*2, o.Interval), o.MaxBackoff)
+			continue
+		}
+		if last != nil {
+			for _, e := range DiffTournaments(last, current) {
+				fn(e)
+			}
+		}
+		last = current
+		wait = o.Interval
+	}
+}
+
+/** spreads d randomly by up to ±fraction */
+func jitter(d time.Duration, fraction float64) time.Duration {
+	if fraction <= 0 {
+		return d
+	}
+	return d + time.Duration((rand.Float64()*2-1)*fraction*float64(d))
+}
@@ -0,0 +1,187 @@
+package challonge
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultSignatureHeader = "X-Challonge-Signature"
+	defaultDeliveryHeader  = "X-Challonge-Delivery"
+	defaultDedupeWindow    = 24 * time.Hour
+	maxWebhookBody         = 1 << 20
+)
+
+/** body of a webhook delivery, resources are wrapped like in API responses */
+type webhookPayload struct {
+	Event string `json:"event"`
+	Id    string `json:"id"`
+	APIResponse
+}
+
+// WebhookHandler is an http.Handler receiving webhook deliveries. Each
+// delivery must carry the hex encoded HMAC-SHA256 of its body, keyed with
+// the shared secret, in the signature header. The event name of the body
+// is used as the Event type, and the enclosed tournament, match and
+// participant are decoded into the event. Redeliveries, recognised by their
+// delivery id or identical body, are acknowledged without being dispatched.
+// A handler without a Secret refuses every delivery with a 500.
+type WebhookHandler struct {
+	Secret          string
+	SignatureHeader string
+	DeliveryHeader  string
+	// DedupeWindow is how long a delivery id is remembered.
+	DedupeWindow time.Duration
+
+	mu       sync.Mutex
+	handlers map[EventType][]func(Event)
+	any      []func(Event)
+	seen     map[string]time.Time
+}
+
+func NewWebhookHandler(secret string) *WebhookHandler {
+	return &WebhookHandler{
+		Secret:          secret,
+		SignatureHeader: defaultSignatureHeader,
+		DeliveryHeader:  defaultDeliveryHeader,
+		DedupeWindow:    defaultDedupeWindow,
+		handlers:        make(map[EventType][]func(Event)),
+		seen:            make(map[string]time.Time),
+	}
+}
+
+/** registers fn for events of type t */
+func (h *WebhookHandler) On(t EventType, fn func(Event)) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.handlers == nil {
+		h.handlers = make(map[EventType][]func(Event))
+	}
+	h.handlers[t] = append(h.handlers[t], fn)
+}
+
+/** registers fn for every event */
+func (h *WebhookHandler) OnAny(fn func(Event)) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.any = append(h.any, fn)
+}
+
+func (h *WebhookHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if h.Secret == "" {
+		// anyone can sign with an empty key, so nothing would be authenticated
+		http.Error(w, "webhook secret not configured", http.StatusInternalServerError)
+		return
+	}
+	body, err := ioutil.ReadAll(http.MaxBytesReader(w, r.Body, maxWebhookBody))
+	if err != nil {
+		http.Error(w, "unable to read body", http.StatusBadRequest)
+		return
+	}
+	if !h.verify(body, r.Header.Get(headerOr(h.SignatureHeader, defaultSignatureHeader))) {
+		http.Error(w, "invalid signature", http.StatusUnauthorized)
+		return
+	}
+	payload := webhookPayload{}
+	if err := json.Unmarshal(body, &payload); err != nil || payload.Event == "" {
+		http.Error(w, "invalid payload", http.StatusBadRequest)
+		return
+	}
+
+	delivery := r.Header.Get(headerOr(h.DeliveryHeader, defaultDeliveryHeader))
+	if delivery == "" {
+		delivery = payload.Id
+	}
+	if delivery == "" {
+		sum := sha256.Sum256(body)
+		delivery = hex.EncodeToString(sum[:])
+	}
+	if !h.firstDelivery(delivery) {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	e := Event{Type: EventType(payload.Event), Participant: payload.Participant}
+	if payload.Tournament != nil {
+		e.Tournament = payload.getTournament()
+	}
+	if payload.Match.Id != 0 {
+		e.Match = &payload.Match
+		if e.Tournament != nil {
+			e.Match.ResolveParticipants(e.Tournament)
+		}
+	}
+	h.mu.Lock()
+	handlers := append(append([]func(Event){}, h.handlers[e.Type]...), h.any...)
+	h.mu.Unlock()
+	for _, fn := range handlers {
+		fn(e)
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+func headerOr(name string, fallback string) string {
+	if name == "" {
+		return fallback
+	}
+	return name
+}
+
+/** checks the hex encoded HMAC-SHA256 signature of body */
+func (h *WebhookHandler) verify(body []byte, signature string) bool {
+	if h.Secret == "" {
+		return false
+	}
+	signature = strings.TrimPrefix(signature, "sha256=")
+	given, err := hex.DecodeString(signature)
+	if err != nil || len(given) == 0 {
+		return false
+	}
+	mac := hmac.New(sha256.New, []byte(h.Secret))
+	mac.Write(body)
+	return hmac.Equal(given, mac.Sum(nil))
+}
+
+/** records a delivery id, returns false when it was already seen within the window */
+func (h *WebhookHandler) firstDelivery(id string) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.seen == nil {
+		h.seen = make(map[string]time.Time)
+	}
+	now := time.Now()
+	window := h.DedupeWindow
+	if window <= 0 {
+		window = defaultDedupeWindow
+	}
+	for k, at := range h.seen {
+		if now.Sub(at) > window {
+			delete(h.seen, k)
+		}
+	}
+	if _, ok := h.seen[id]; ok {
+		return false
+	}
+	h.seen[id] = now
+	return true
+}
+
+// SignWebhook returns the signature header value for body, as expected by
+// WebhookHandler. It is meant for tests and for relaying deliveries.
+func SignWebhook(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}